@@ -2,19 +2,26 @@ package app
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"sync"
 
 	cmtconfig "github.com/cometbft/cometbft/config"
+	"github.com/cometbft/cometbft/crypto"
 	cmtflags "github.com/cometbft/cometbft/libs/cli/flags"
 	cmtlog "github.com/cometbft/cometbft/libs/log"
 	cmtnm "github.com/cometbft/cometbft/node"
 	cmtp2p "github.com/cometbft/cometbft/p2p"
 	"github.com/cometbft/cometbft/privval"
 	"github.com/cometbft/cometbft/proxy"
+	cmttypes "github.com/cometbft/cometbft/types"
 	"github.com/sonata-labs/sonata/config"
 	"github.com/sonata-labs/sonata/core"
+	"github.com/sonata-labs/sonata/gen/api/v1/v1connect"
+	chainv1 "github.com/sonata-labs/sonata/gen/chain/v1"
 	"github.com/sonata-labs/sonata/store/chainstore"
 	"github.com/sonata-labs/sonata/store/localstore"
+	"github.com/sonata-labs/sonata/types/module"
 	"github.com/sonata-labs/sonata/x/account"
 	"github.com/sonata-labs/sonata/x/chain"
 	"github.com/sonata-labs/sonata/x/composition"
@@ -37,22 +44,21 @@ type App struct {
 
 	server *server.Server
 
-	chain       *chain.ChainService
-	storage     *storage.StorageService
-	system      *system.SystemService
-	p2p         *p2p.P2PService
-	ddex        *ddex.DDEXService
-	composition *composition.CompositionService
-	account     *account.AccountService
-	validator   *validator.ValidatorService
-	statesync   *statesync.StateSyncService
+	// every module the app runs, the default Sonata modules first
+	modules []*Module
+	// modules, core and server in the order they start
+	order []*Module
+
+	mu       sync.Mutex
+	started  []*Module
+	stopping bool
 
 	chainStore *chainstore.ChainStore
 	localStore *localstore.LocalStore
 }
 
 // Creates and initializes all modules and the app
-func NewApp(cfg *config.Config, zapLogger *zap.Logger) (*App, error) {
+func NewApp(cfg *config.Config, zapLogger *zap.Logger, opts ...Option) (*App, error) {
 	appLogger := zapLogger.Named("app").Sugar()
 
 	chainStore, err := chainstore.NewChainStore(cfg.Sonata.ChainStore.Path)
@@ -72,18 +78,57 @@ func NewApp(cfg *config.Config, zapLogger *zap.Logger) (*App, error) {
 		cmtConfig.PrivValidatorStateFile(),
 	)
 
+	// transactions are signed for the configured chain id, so it has to be
+	// the chain this node actually follows
+	genDoc, err := cmttypes.GenesisDocFromFile(cmtConfig.GenesisFile())
+	if err != nil {
+		return nil, err
+	}
+	if genDoc.ChainID != cfg.Sonata.ChainID {
+		return nil, fmt.Errorf("chain_id %q does not match genesis chain id %q", cfg.Sonata.ChainID, genDoc.ChainID)
+	}
+
 	nodeKey, err := cmtp2p.LoadNodeKey(cmtConfig.NodeKeyFile())
 	if err != nil {
 		appLogger.Fatalf("failed to load node's key: %v", err)
 	}
 
+	// the node signs its transactions with the node key, so they are sent
+	// from the account bound to that key
+	nodeAddress := nodeKey.PrivKey.PubKey().Address().String()
+	if cfg.Sonata.ValidatorAddress != "" && cfg.Sonata.ValidatorAddress != nodeAddress {
+		return nil, fmt.Errorf("validator_address %q does not match the node key address %q", cfg.Sonata.ValidatorAddress, nodeAddress)
+	}
+
 	cmtLogger := cmtlog.NewTMLogger(cmtlog.NewSyncWriter(os.Stdout))
 	cmtLogger, err = cmtflags.ParseLogLevel(cmtConfig.LogLevel, cmtLogger, cmtconfig.DefaultLogLevel)
 	if err != nil {
 		return nil, err
 	}
 
+	env := &Env{
+		Config:     cfg,
+		Logger:     zapLogger,
+		ChainStore: chainStore,
+		LocalStore: localStore,
+		Chain:      chain.NewChainService(cfg, zapLogger),
+	}
+
+	modules, err := buildModules(env, nodeKey.PrivKey, opts)
+	if err != nil {
+		return nil, err
+	}
+	// a broken dependency graph is rejected before the node is created
+	if _, err := startOrder(modules); err != nil {
+		return nil, err
+	}
+
+	// The handshake in NewNode runs InitChain on a new chain and replays the
+	// blocks the chain store has not committed, so everything that executes
+	// blocks is registered before the node is created.
 	createNode := func(c *core.Core) (*cmtnm.Node, error) {
+		registerModules(c, modules)
+
 		return cmtnm.NewNode(context.Background(), cmtConfig, pv, nodeKey, proxy.NewLocalClientCreator(c),
 			cmtnm.DefaultGenesisDocProviderFunc(cmtConfig),
 			cmtconfig.DefaultDBProvider,
@@ -94,103 +139,207 @@ func NewApp(cfg *config.Config, zapLogger *zap.Logger) (*App, error) {
 	if err != nil {
 		return nil, err
 	}
+	env.Chain.SetNode(node)
 
-	chainSvc := chain.NewChainService(cfg, zapLogger, node)
-	storageSvc, err := storage.NewStorageService(cfg, zapLogger, localStore, chainStore)
+	serverSvc, err := server.NewServer(cfg, zapLogger)
 	if err != nil {
 		return nil, err
 	}
+	// Connect services are served for the module implementing them
+	for _, m := range modules {
+		for _, h := range m.Handlers {
+			serverSvc.RegisterHandler(m.Name(), h.Path, h.Handler)
+		}
+	}
 
-	// Set dependencies
-	storageSvc.SetChain(chainSvc)
-	systemSvc := system.NewSystemService(cfg, zapLogger)
-	p2pSvc := p2p.NewP2PService(cfg, zapLogger)
-	ddexSvc := ddex.NewDDEXService(cfg, zapLogger)
-	compositionSvc := composition.NewCompositionService(cfg, zapLogger)
-	accountSvc := account.NewAccountService(cfg, zapLogger, chainStore)
-	validatorSvc := validator.NewValidatorService(cfg, zapLogger)
-	statesyncSvc := statesync.NewStateSyncService(cfg, zapLogger, chainStore)
-
-	coreSvc.RegisterModules(core.InitChain, chainSvc)
-	coreSvc.RegisterModules(core.CheckTx, chainSvc, accountSvc, ddexSvc, storageSvc, compositionSvc, validatorSvc)
-	coreSvc.RegisterModules(core.PrepareProposal, chainSvc, storageSvc, systemSvc, ddexSvc, compositionSvc, accountSvc, validatorSvc)
-	coreSvc.RegisterModules(core.ProcessProposal, chainSvc, storageSvc, systemSvc, ddexSvc, compositionSvc, accountSvc, validatorSvc)
-	coreSvc.RegisterModules(core.FinalizeBlock, chainSvc, storageSvc, systemSvc, ddexSvc, compositionSvc, accountSvc, validatorSvc, statesyncSvc)
-	coreSvc.RegisterModules(core.Commit, chainSvc, statesyncSvc)
-
-	coreSvc.RegisterModules(core.ListSnapshots, statesyncSvc)
-	coreSvc.RegisterModules(core.OfferSnapshot, statesyncSvc)
-	coreSvc.RegisterModules(core.LoadSnapshotChunk, statesyncSvc)
-	coreSvc.RegisterModules(core.ApplySnapshotChunk, statesyncSvc)
-
-	serverSvc, err := server.NewServer(cfg, zapLogger, chainSvc, storageSvc, systemSvc, p2pSvc, ddexSvc, compositionSvc, accountSvc, validatorSvc)
+	// Core starts CometBFT once every module is ready and the server takes
+	// traffic once core is, so they stop in the opposite order
+	names := make([]string, 0, len(modules))
+	for _, m := range modules {
+		names = append(names, m.Name())
+	}
+	lifecycle := append(append([]*Module{}, modules...),
+		&Module{Module: coreSvc, DependsOn: names},
+		&Module{Module: serverSvc, DependsOn: []string{coreSvc.Name()}},
+	)
+	order, err := startOrder(lifecycle)
 	if err != nil {
 		return nil, err
 	}
 
-	// Register startup dependencies
-	// Core waits for all modules to be ready before starting CometBFT
-	coreSvc.RegisterStartupDeps(
-		chainSvc.Ready(), storageSvc.Ready(), systemSvc.Ready(),
-		p2pSvc.Ready(), ddexSvc.Ready(), compositionSvc.Ready(),
-		accountSvc.Ready(), validatorSvc.Ready(), statesyncSvc.Ready(),
-	)
-	// Server waits for core to be ready before accepting HTTP traffic
-	serverSvc.RegisterStartupDeps(coreSvc.Ready())
-
-	// Register shutdown dependencies (reverse order of startup)
-	// Server shuts down first (no deps)
-	// Core waits for server to stop before shutting down
-	coreSvc.RegisterShutdownDeps(serverSvc.Stopped())
-
-	// All modules wait for core to stop before shutting down
-	chainSvc.RegisterShutdownDeps(coreSvc.Stopped())
-	storageSvc.RegisterShutdownDeps(coreSvc.Stopped())
-	systemSvc.RegisterShutdownDeps(coreSvc.Stopped())
-	p2pSvc.RegisterShutdownDeps(coreSvc.Stopped())
-	ddexSvc.RegisterShutdownDeps(coreSvc.Stopped())
-	compositionSvc.RegisterShutdownDeps(coreSvc.Stopped())
-	accountSvc.RegisterShutdownDeps(coreSvc.Stopped())
-	validatorSvc.RegisterShutdownDeps(coreSvc.Stopped())
-	statesyncSvc.RegisterShutdownDeps(coreSvc.Stopped())
-
 	return &App{
 		core:   coreSvc,
 		config: cfg,
 		node:   node,
 		logger: appLogger,
 
-		chain:       chainSvc,
-		server:      serverSvc,
-		storage:     storageSvc,
-		system:      systemSvc,
-		p2p:         p2pSvc,
-		ddex:        ddexSvc,
-		composition: compositionSvc,
-		account:     accountSvc,
-		validator:   validatorSvc,
-		statesync:   statesyncSvc,
+		server:  serverSvc,
+		modules: modules,
+		order:   order,
 
 		chainStore: chainStore,
 		localStore: localStore,
 	}, nil
 }
 
+// registerModules registers everything core needs from the modules to
+// execute blocks.
+func registerModules(c *core.Core, modules []*Module) {
+	for _, m := range modules {
+		// Genesis app state is imported by the module owning it
+		if m.Genesis != nil {
+			c.RegisterGenesisHandler(m.Name(), m.Genesis.Init)
+		}
+		// Upgrade plans reached by the chain are applied by this binary
+		for name, handler := range m.Upgrades {
+			c.RegisterUpgradeHandler(name, handler)
+		}
+		// Transactions are decoded by core and routed to the module owning the body
+		if msgModule, ok := m.Module.(module.MsgModule); ok {
+			msgModule.RegisterMsgHandlers(c)
+		}
+		// State queries are routed by path to the module owning the state
+		if queryModule, ok := m.Module.(module.QueryModule); ok {
+			queryModule.RegisterQueryHandlers(c)
+		}
+		// Side effects run once a block is committed
+		if hookModule, ok := m.Module.(module.CommitHookModule); ok {
+			hookModule.RegisterCommitHooks(c)
+		}
+		// Per block logic runs around the block's transactions
+		if beginBlocker, ok := m.Module.(module.BeginBlocker); ok {
+			c.RegisterBeginBlocker(beginBlocker)
+		}
+		if endBlocker, ok := m.Module.(module.EndBlocker); ok {
+			c.RegisterEndBlocker(endBlocker)
+		}
+	}
+
+	callbacks := make(map[core.Callback][]module.Module)
+	for _, m := range modules {
+		for _, callback := range m.Callbacks {
+			callbacks[callback] = append(callbacks[callback], m.Module)
+		}
+	}
+	for callback, mods := range callbacks {
+		c.RegisterModules(callback, mods...)
+	}
+}
+
+// buildModules builds the default Sonata modules followed by the ones added
+// with opts, rejecting two modules with the same name.
+func buildModules(env *Env, signer crypto.PrivKey, opts []Option) ([]*Module, error) {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	modules, err := defaultModules(env, signer)
+	if err != nil {
+		return nil, err
+	}
+	for _, factory := range o.modules {
+		m, err := factory(env)
+		if err != nil {
+			return nil, err
+		}
+		modules = append(modules, m)
+	}
+	if _, err := moduleByName(modules); err != nil {
+		return nil, err
+	}
+	return modules, nil
+}
+
+// defaultModules builds the Sonata modules, in the order they run in each
+// ABCI callback and import their genesis state.
+func defaultModules(env *Env, signer crypto.PrivKey) ([]*Module, error) {
+	cfg, logger := env.Config, env.Logger
+
+	storageSvc, err := storage.NewStorageService(cfg, logger, env.LocalStore, env.ChainStore, signer)
+	if err != nil {
+		return nil, err
+	}
+	// Set dependencies
+	storageSvc.SetChain(env.Chain)
+
+	systemSvc := system.NewSystemService(cfg, logger)
+	p2pSvc := p2p.NewP2PService(cfg, logger)
+	ddexSvc := ddex.NewDDEXService(cfg, logger)
+	compositionSvc := composition.NewCompositionService(cfg, logger)
+	accountSvc := account.NewAccountService(cfg, logger, env.ChainStore)
+	validatorSvc := validator.NewValidatorService(cfg, logger)
+	statesyncSvc := statesync.NewStateSyncService(cfg, logger, env.ChainStore)
+
+	proposal := []core.Callback{core.CheckTx, core.PrepareProposal, core.ProcessProposal, core.FinalizeBlock}
+	return []*Module{
+		{
+			Module:    env.Chain,
+			Callbacks: append([]core.Callback{core.InitChain, core.Commit}, proposal...),
+			Handlers:  handler(v1connect.NewChainHandler(env.Chain)),
+			Genesis:   chainGenesis,
+		},
+		{
+			// accounts are imported before the state referring to them
+			Module:    accountSvc,
+			Callbacks: proposal,
+			Handlers:  handler(v1connect.NewAccountHandler(accountSvc)),
+			Genesis:   accountGenesis,
+		},
+		{
+			Module:    storageSvc,
+			Callbacks: proposal,
+			Handlers:  handler(v1connect.NewStorageHandler(storageSvc)),
+			Genesis:   storageGenesis,
+			Upgrades: map[string]module.UpgradeHandler{
+				// the chain store migration indexes existing uploads by uploader
+				chainstore.UploaderIndexUpgrade: func(context.Context, *chainstore.ChainStore, *chainv1.UpgradePlan) error {
+					return nil
+				},
+			},
+		},
+		{
+			Module:    systemSvc,
+			Callbacks: []core.Callback{core.PrepareProposal, core.ProcessProposal, core.FinalizeBlock},
+			Handlers:  handler(v1connect.NewSystemHandler(systemSvc)),
+		},
+		{
+			Module:   p2pSvc,
+			Handlers: handler(v1connect.NewP2PHandler(p2pSvc)),
+		},
+		{
+			Module:    ddexSvc,
+			Callbacks: proposal,
+			Handlers:  handler(v1connect.NewDDEXHandler(ddexSvc)),
+			Genesis:   ddexGenesis,
+		},
+		{
+			Module:    compositionSvc,
+			Callbacks: proposal,
+			Handlers:  handler(v1connect.NewCompositionHandler(compositionSvc)),
+		},
+		{
+			Module:    validatorSvc,
+			Callbacks: proposal,
+			Handlers:  handler(v1connect.NewValidatorHandler(validatorSvc)),
+		},
+		{
+			Module: statesyncSvc,
+			Callbacks: []core.Callback{
+				core.FinalizeBlock, core.Commit,
+				core.ListSnapshots, core.OfferSnapshot, core.LoadSnapshotChunk, core.ApplySnapshotChunk,
+			},
+		},
+	}, nil
+}
+
 func (app *App) Run(ctx context.Context) error {
 	eg, ctx := errgroup.WithContext(ctx)
 
 	// start up all modules
-	eg.Go(app.server.Start)
-	eg.Go(app.core.Start)
-	eg.Go(app.ddex.Start)
-	eg.Go(app.composition.Start)
-	eg.Go(app.account.Start)
-	eg.Go(app.validator.Start)
-	eg.Go(app.chain.Start)
-	eg.Go(app.storage.Start)
-	eg.Go(app.system.Start)
-	eg.Go(app.p2p.Start)
-	eg.Go(app.statesync.Start)
+	eg.Go(func() error {
+		return app.start(ctx, eg)
+	})
 
 	eg.Go(func() error {
 		<-ctx.Done()
@@ -202,20 +351,6 @@ func (app *App) Run(ctx context.Context) error {
 }
 
 func (app *App) Shutdown() error {
-	eg, _ := errgroup.WithContext(context.Background())
 	defer app.logger.Info("shutdown complete")
-
-	eg.Go(app.server.Stop)
-	eg.Go(app.core.Stop)
-	eg.Go(app.ddex.Stop)
-	eg.Go(app.composition.Stop)
-	eg.Go(app.account.Stop)
-	eg.Go(app.validator.Stop)
-	eg.Go(app.chain.Stop)
-	eg.Go(app.storage.Stop)
-	eg.Go(app.system.Stop)
-	eg.Go(app.p2p.Stop)
-	eg.Go(app.statesync.Stop)
-
-	return eg.Wait()
+	return app.stop()
 }
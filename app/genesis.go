@@ -0,0 +1,115 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	cmtp2p "github.com/cometbft/cometbft/p2p"
+	"github.com/sonata-labs/sonata/config"
+	chainv1 "github.com/sonata-labs/sonata/gen/chain/v1"
+	"github.com/sonata-labs/sonata/store/chainstore"
+	"github.com/sonata-labs/sonata/store/localstore"
+	"github.com/sonata-labs/sonata/x/account"
+	"github.com/sonata-labs/sonata/x/chain"
+	"github.com/sonata-labs/sonata/x/ddex"
+	"github.com/sonata-labs/sonata/x/storage"
+	"go.uber.org/zap"
+)
+
+// The genesis of the default modules. The schema version is claimed by the
+// chain module, as InitChain records it after the import.
+var (
+	chainGenesis = &Genesis{Init: chain.InitGenesis, Export: chain.ExportGenesis, Prefixes: []string{
+		chainstore.ParamsPrefix, chainstore.UpgradePrefix, "schema/",
+	}}
+	accountGenesis = &Genesis{Init: account.InitGenesis, Export: account.ExportGenesis, Prefixes: []string{
+		chainstore.AccountPrefix,
+	}}
+	storageGenesis = &Genesis{Init: storage.InitGenesis, Export: storage.ExportGenesis, Prefixes: []string{
+		chainstore.UploadPrefix, chainstore.UploadOriginalPrefix, chainstore.UploadUploaderPrefix,
+	}}
+	ddexGenesis = &Genesis{Init: ddex.InitGenesis, Export: ddex.ExportGenesis, Prefixes: []string{
+		chainstore.DDEXPrefix,
+	}}
+)
+
+// ExportGenesis reads the committed chain store of a stopped node into a
+// genesis app state, returning it with the height it was committed at. opts
+// must add the modules the node runs with, as each module exports its own
+// part of the state.
+func ExportGenesis(ctx context.Context, cfg *config.Config, opts ...Option) (*chainv1.GenesisState, int64, error) {
+	chainStore, err := chainstore.NewChainStore(cfg.Sonata.ChainStore.Path)
+	if err != nil {
+		return nil, 0, fmt.Errorf("opening chain store: %w", err)
+	}
+	defer chainStore.Close()
+
+	localStore, err := localstore.NewLocalStore(cfg.Sonata.LocalStore.Path)
+	if err != nil {
+		return nil, 0, fmt.Errorf("opening local store: %w", err)
+	}
+	defer localStore.Close()
+
+	nodeKey, err := cmtp2p.LoadNodeKey(cfg.CometBFT.NodeKeyFile())
+	if err != nil {
+		return nil, 0, fmt.Errorf("loading node key: %w", err)
+	}
+
+	logger := zap.NewNop()
+	env := &Env{
+		Config:     cfg,
+		Logger:     logger,
+		ChainStore: chainStore,
+		LocalStore: localStore,
+		Chain:      chain.NewChainService(cfg, logger),
+	}
+	modules, err := buildModules(env, nodeKey.PrivKey, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	height, _, err := chainStore.GetLastBlock()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	state, err := exportGenesis(ctx, chainStore, modules)
+	if err != nil {
+		return nil, 0, err
+	}
+	return state, height, nil
+}
+
+// exportGenesis exports the state of store through the genesis of modules.
+// It fails if the state holds a key no module exports, rather than leaving
+// it out of the genesis.
+func exportGenesis(ctx context.Context, store *chainstore.ChainStore, modules []*Module) (*chainv1.GenesisState, error) {
+	err := store.IterateState(func(key []byte) error {
+		for _, m := range modules {
+			if m.Genesis == nil {
+				continue
+			}
+			for _, prefix := range m.Genesis.Prefixes {
+				if strings.HasPrefix(string(key), prefix) {
+					return nil
+				}
+			}
+		}
+		return fmt.Errorf("state key %q is not exported by any module", key)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	state := &chainv1.GenesisState{}
+	for _, m := range modules {
+		if m.Genesis == nil {
+			continue
+		}
+		if err := m.Genesis.Export(ctx, store, state); err != nil {
+			return nil, fmt.Errorf("exporting %s genesis: %w", m.Name(), err)
+		}
+	}
+	return state, nil
+}
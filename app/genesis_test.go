@@ -0,0 +1,303 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cometbft/cometbft/crypto/ed25519"
+	accountv1 "github.com/sonata-labs/sonata/gen/account/v1"
+	chainv1 "github.com/sonata-labs/sonata/gen/chain/v1"
+	storagev1 "github.com/sonata-labs/sonata/gen/storage/v1"
+	"github.com/sonata-labs/sonata/store/chainstore"
+	"github.com/sonata-labs/sonata/types/module"
+	"github.com/sonata-labs/sonata/types/signing"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// testModule is a module that is never started, carrying only its genesis.
+type testModule struct {
+	*module.BaseModule
+	name string
+}
+
+func (m *testModule) Name() string {
+	return m.name
+}
+
+// genesisModules returns modules with the genesis of the default modules, in
+// the order they import it, followed by extra.
+func genesisModules(extra ...*Module) []*Module {
+	modules := []*Module{
+		{Module: &testModule{name: "chain"}, Genesis: chainGenesis},
+		{Module: &testModule{name: "account"}, Genesis: accountGenesis},
+		{Module: &testModule{name: "storage"}, Genesis: storageGenesis},
+		{Module: &testModule{name: "ddex"}, Genesis: ddexGenesis},
+	}
+	return append(modules, extra...)
+}
+
+// initGenesis runs the genesis import of modules on store.
+func initGenesis(store *chainstore.ChainStore, modules []*Module, state *chainv1.GenesisState) error {
+	for _, m := range modules {
+		if err := m.Genesis.Init(context.Background(), store, state); err != nil {
+			return fmt.Errorf("importing %s genesis: %w", m.Name(), err)
+		}
+	}
+	return nil
+}
+
+// importGenesis imports state into a new chain store the way InitChain does,
+// returning the store and its genesis app hash.
+func importGenesis(t *testing.T, modules []*Module, state *chainv1.GenesisState) (*chainstore.ChainStore, []byte) {
+	t.Helper()
+	store, err := chainstore.NewChainStore(filepath.Join(t.TempDir(), "chainstore"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	batch := store.Batch()
+	if err := initGenesis(batch, modules, state); err != nil {
+		t.Fatal(err)
+	}
+	if err := batch.InitSchema(); err != nil {
+		t.Fatal(err)
+	}
+	appHash, err := batch.ComputeAppHash(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := batch.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	return store, appHash
+}
+
+// testGenesis returns a genesis state with every kind of record set.
+func testGenesis(t *testing.T) *chainv1.GenesisState {
+	t.Helper()
+	state := &chainv1.GenesisState{
+		Params:      chainstore.DefaultParams(),
+		UpgradePlan: &chainv1.UpgradePlan{Name: "v3", Height: 100, Info: "next release"},
+		UpgradesDone: []*chainv1.UpgradeDone{
+			{Name: "v1", Height: 10},
+			{Name: "v2", Height: 50},
+		},
+	}
+	for i := 0; i < 3; i++ {
+		pubKey := ed25519.GenPrivKey().PubKey()
+		state.Accounts = append(state.Accounts, &accountv1.Account{
+			Address: pubKey.Address().String(),
+			PubKey:  signing.EncodePubKey(pubKey),
+			Balance: uint64(1000 * i),
+			Nonce:   uint64(i),
+		})
+		state.Uploads = append(state.Uploads, &storagev1.FileUploadMessage{
+			UploaderAddress:   state.Accounts[i].Address,
+			TranscoderAddress: state.Accounts[0].Address,
+			OriginalCid:       fmt.Sprintf("original-%d", i),
+			TranscodedCid:     fmt.Sprintf("transcoded-%d", i),
+			FileName:          fmt.Sprintf("track-%d.wav", i),
+			MimeType:          "audio/mpeg",
+			Size:              uint64(100 + i),
+		})
+	}
+
+	// every ddex kind, in the json form a genesis file carries, submitted by
+	// two accounts that reuse each other's message ids
+	ddex := map[string][]string{
+		"releases":      {"v381", "v43"},
+		"catalogLists":  {"v381", "v383"},
+		"purgeReleases": {"v381", "v432"},
+		"pies":          {"v10"},
+		"pieRequests":   {"v10"},
+		"meads":         {"v11"},
+	}
+	var fields []string
+	for field, versions := range ddex {
+		var msgs []string
+		for i, version := range versions {
+			msgs = append(msgs, fmt.Sprintf(`{%q: {"messageHeader": {"messageId": "%s-%d"}}}`, version, field, i))
+		}
+		fields = append(fields, fmt.Sprintf("%q: [%s]", field, strings.Join(msgs, ",")))
+	}
+	for _, account := range state.Accounts[:2] {
+		msgs := &chainv1.GenesisDDEXMessages{}
+		if err := protojson.Unmarshal([]byte("{"+strings.Join(fields, ",")+"}"), msgs); err != nil {
+			t.Fatal(err)
+		}
+		msgs.Address = account.Address
+		state.Ddex = append(state.Ddex, msgs)
+	}
+	return state
+}
+
+// ddexMessages counts the genesis DDEX messages of every kind and account.
+func ddexMessages(state *chainv1.GenesisState) int {
+	var n int
+	for _, msgs := range state.Ddex {
+		n += len(msgs.Releases) + len(msgs.CatalogLists) + len(msgs.PurgeReleases) +
+			len(msgs.Pies) + len(msgs.PieRequests) + len(msgs.Meads)
+	}
+	return n
+}
+
+func TestExportGenesisRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		state func(t *testing.T) *chainv1.GenesisState
+	}{
+		{name: "empty", state: func(t *testing.T) *chainv1.GenesisState { return &chainv1.GenesisState{} }},
+		{name: "every record", state: testGenesis},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := tt.state(t)
+			store, appHash := importGenesis(t, genesisModules(), state)
+
+			exported, err := exportGenesis(context.Background(), store, genesisModules())
+			if err != nil {
+				t.Fatalf("exporting genesis: %v", err)
+			}
+			for _, count := range []struct {
+				name      string
+				got, want int
+			}{
+				{"accounts", len(exported.Accounts), len(state.Accounts)},
+				{"uploads", len(exported.Uploads), len(state.Uploads)},
+				{"ddex messages", ddexMessages(exported), ddexMessages(state)},
+				{"upgrades done", len(exported.UpgradesDone), len(state.UpgradesDone)},
+			} {
+				if count.got != count.want {
+					t.Errorf("exported %d %s, want %d", count.got, count.name, count.want)
+				}
+			}
+			if !proto.Equal(exported.UpgradePlan, state.UpgradePlan) {
+				t.Errorf("exported upgrade plan %v, want %v", exported.UpgradePlan, state.UpgradePlan)
+			}
+
+			reimported, reimportedHash := importGenesis(t, genesisModules(), exported)
+			if !bytes.Equal(appHash, reimportedHash) {
+				t.Errorf("app hash after round trip is %X, want %X", reimportedHash, appHash)
+			}
+			reexported, err := exportGenesis(context.Background(), reimported, genesisModules())
+			if err != nil {
+				t.Fatalf("exporting reimported genesis: %v", err)
+			}
+			if !proto.Equal(exported, reexported) {
+				t.Errorf("genesis changed on round trip\nfirst:  %v\nsecond: %v", exported, reexported)
+			}
+		})
+	}
+}
+
+func TestExportGenesisUnclaimedKey(t *testing.T) {
+	store, _ := importGenesis(t, genesisModules(), testGenesis(t))
+
+	// a record written under a prefix no genesis module exports
+	batch := store.Batch()
+	if err := chainstore.NewCollection("unexported/", chainstore.StringKey, func() *chainv1.UpgradePlan {
+		return &chainv1.UpgradePlan{}
+	}).Set(batch, "plan", &chainv1.UpgradePlan{Name: "lost"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := batch.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := exportGenesis(context.Background(), store, genesisModules()); err == nil || !strings.Contains(err.Error(), "unexported/plan") {
+		t.Fatalf("exporting genesis with an unclaimed key: got %v, want an error naming the key", err)
+	}
+}
+
+func TestExportGenesisAddedModule(t *testing.T) {
+	// a module added to the app keeping one upgrade plan of its own
+	plans := chainstore.NewCollection("extra/", chainstore.StringKey, func() *chainv1.UpgradePlan {
+		return &chainv1.UpgradePlan{}
+	})
+	extra := &Module{Module: &testModule{name: "extra"}, Genesis: &Genesis{
+		Init: func(ctx context.Context, store *chainstore.ChainStore, state *chainv1.GenesisState) error {
+			plan := &chainv1.UpgradePlan{}
+			if err := state.Modules["extra"].UnmarshalTo(plan); err != nil {
+				return err
+			}
+			return plans.Set(store, plan.Name, plan)
+		},
+		Export: func(ctx context.Context, store *chainstore.ChainStore, state *chainv1.GenesisState) error {
+			return plans.Iterate(store, func(_ string, plan *chainv1.UpgradePlan) error {
+				a, err := anypb.New(plan)
+				if err != nil {
+					return err
+				}
+				state.Modules = map[string]*anypb.Any{"extra": a}
+				return nil
+			})
+		},
+		Prefixes: []string{"extra/"},
+	}}
+
+	state := testGenesis(t)
+	plan, err := anypb.New(&chainv1.UpgradePlan{Name: "extra", Height: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.Modules = map[string]*anypb.Any{"extra": plan}
+	store, _ := importGenesis(t, genesisModules(extra), state)
+
+	if _, err := exportGenesis(context.Background(), store, genesisModules()); err == nil || !strings.Contains(err.Error(), "extra/extra") {
+		t.Fatalf("exporting without the added module: got %v, want an error naming its key", err)
+	}
+	exported, err := exportGenesis(context.Background(), store, genesisModules(extra))
+	if err != nil {
+		t.Fatalf("exporting genesis: %v", err)
+	}
+	if !proto.Equal(exported.Modules["extra"], plan) {
+		t.Errorf("exported module state %v, want %v", exported.Modules["extra"], plan)
+	}
+}
+
+func TestInitGenesisRejectsDuplicates(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(state *chainv1.GenesisState)
+	}{
+		{"account", func(s *chainv1.GenesisState) { s.Accounts = append(s.Accounts, s.Accounts[0]) }},
+		{"transcoded cid", func(s *chainv1.GenesisState) {
+			dup := proto.Clone(s.Uploads[0]).(*storagev1.FileUploadMessage)
+			dup.OriginalCid = "another-original"
+			s.Uploads = append(s.Uploads, dup)
+		}},
+		{"original cid", func(s *chainv1.GenesisState) {
+			dup := proto.Clone(s.Uploads[0]).(*storagev1.FileUploadMessage)
+			dup.TranscodedCid = "another-transcoded"
+			s.Uploads = append(s.Uploads, dup)
+		}},
+		{"ddex message", func(s *chainv1.GenesisState) { s.Ddex[0].Meads = append(s.Ddex[0].Meads, s.Ddex[0].Meads[0]) }},
+		{"ddex account", func(s *chainv1.GenesisState) {
+			s.Ddex = append(s.Ddex, &chainv1.GenesisDDEXMessages{Address: s.Ddex[0].Address})
+		}},
+		{"applied upgrade", func(s *chainv1.GenesisState) { s.UpgradesDone = append(s.UpgradesDone, s.UpgradesDone[0]) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := testGenesis(t)
+			tt.modify(state)
+
+			store, err := chainstore.NewChainStore(filepath.Join(t.TempDir(), "chainstore"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer store.Close()
+			err = initGenesis(store.Batch(), genesisModules(), state)
+			if err == nil || !strings.Contains(err.Error(), "duplicate") {
+				t.Fatalf("importing a duplicate %s: got %v, want a duplicate error", tt.name, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// DefaultStartTimeout is how long a module has to become ready once it
+	// is started, unless it sets its own StartTimeout.
+	DefaultStartTimeout = 30 * time.Second
+
+	// DefaultStopTimeout is how long a module has to stop, unless it sets
+	// its own StopTimeout.
+	DefaultStopTimeout = 15 * time.Second
+)
+
+func (m *Module) startTimeout() time.Duration {
+	if m.StartTimeout > 0 {
+		return m.StartTimeout
+	}
+	return DefaultStartTimeout
+}
+
+func (m *Module) stopTimeout() time.Duration {
+	if m.StopTimeout > 0 {
+		return m.StopTimeout
+	}
+	return DefaultStopTimeout
+}
+
+// startOrder sorts modules so that each one comes after the modules it
+// depends on, keeping the order they were added otherwise. Unknown
+// dependencies and dependency cycles are rejected.
+func startOrder(modules []*Module) ([]*Module, error) {
+	byName, err := moduleByName(modules)
+	if err != nil {
+		return nil, err
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(modules))
+	order := make([]*Module, 0, len(modules))
+	var path []string
+
+	var visit func(m *Module) error
+	visit = func(m *Module) error {
+		name := m.Name()
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			// the cycle is the part of the path starting at this module
+			for i, n := range path {
+				if n == name {
+					cycle := append(append([]string{}, path[i:]...), name)
+					return fmt.Errorf("module dependency cycle: %s", strings.Join(cycle, " -> "))
+				}
+			}
+		}
+
+		state[name] = visiting
+		path = append(path, name)
+		for _, depName := range m.DependsOn {
+			dep, ok := byName[depName]
+			if !ok {
+				return fmt.Errorf("module %q depends on unknown module %q", name, depName)
+			}
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = visited
+		order = append(order, m)
+		return nil
+	}
+
+	for _, m := range modules {
+		if err := visit(m); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// safely runs a lifecycle method of a module, turning a panic into an error
+// naming the module.
+func safely(name, method string, fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("module %s panicked in %s: %v", name, method, r)
+		}
+	}()
+	if err := fn(); err != nil {
+		return fmt.Errorf("module %s: %w", name, err)
+	}
+	return nil
+}
+
+// start starts the modules one at a time in dependency order, waiting for
+// each to become ready before starting the next. Start runs for as long as
+// the module does, so it is run in eg.
+func (app *App) start(ctx context.Context, eg *errgroup.Group) error {
+	for _, m := range app.order {
+		app.mu.Lock()
+		if app.stopping {
+			app.mu.Unlock()
+			return nil
+		}
+		app.started = append(app.started, m)
+		app.mu.Unlock()
+
+		name := m.Name()
+		eg.Go(func() error {
+			return safely(name, "start", m.Start)
+		})
+
+		timeout := m.startTimeout()
+		select {
+		case <-m.Ready():
+		case <-ctx.Done():
+			return nil
+		case <-time.After(timeout):
+			return fmt.Errorf("module %s not ready after %s", name, timeout)
+		}
+	}
+	return nil
+}
+
+// stop stops the started modules in reverse dependency order. A module that
+// fails or does not stop in time is reported and the rest are still stopped.
+func (app *App) stop() error {
+	app.mu.Lock()
+	app.stopping = true
+	started := app.started
+	app.mu.Unlock()
+
+	var errs []error
+	for i := len(started) - 1; i >= 0; i-- {
+		m := started[i]
+		name := m.Name()
+
+		done := make(chan error, 1)
+		go func() {
+			done <- safely(name, "stop", m.Stop)
+		}()
+
+		timeout := m.stopTimeout()
+		select {
+		case err := <-done:
+			if err != nil {
+				app.logger.Errorw("failed to stop module", "module", name, "error", err)
+				errs = append(errs, err)
+			}
+		case <-time.After(timeout):
+			err := fmt.Errorf("module %s did not stop within %s", name, timeout)
+			app.logger.Error(err)
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,120 @@
+package app
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sonata-labs/sonata/config"
+	"github.com/sonata-labs/sonata/core"
+	"github.com/sonata-labs/sonata/store/chainstore"
+	"github.com/sonata-labs/sonata/store/localstore"
+	"github.com/sonata-labs/sonata/types/module"
+	"github.com/sonata-labs/sonata/x/chain"
+	"go.uber.org/zap"
+)
+
+// Module is a module run by the app, along with how it is wired in.
+type Module struct {
+	module.Module
+
+	// Callbacks are the ABCI callbacks the module runs in. Modules run in
+	// the order they were added to the app, default modules first.
+	Callbacks []core.Callback
+
+	// Handlers are the Connect services the module serves over HTTP.
+	Handlers []Handler
+
+	// DependsOn names the modules that must be ready before this one
+	// starts. They stop after it does. Core starts once every module is
+	// ready.
+	DependsOn []string
+
+	// Genesis imports and exports the module's part of the genesis app
+	// state, if it keeps state on chain. Genesis is imported in module
+	// order.
+	Genesis *Genesis
+
+	// Upgrades are the upgrade plans the module knows how to apply, by
+	// name. A release that changes how blocks execute adds its migration
+	// here, and the upgrade authority schedules a plan with the same name;
+	// binaries without it halt at the plan's height until operators switch
+	// to one that has it.
+	Upgrades map[string]module.UpgradeHandler
+
+	// StartTimeout bounds how long the module takes to become ready and
+	// StopTimeout how long it takes to stop. Zero means DefaultStartTimeout
+	// and DefaultStopTimeout.
+	StartTimeout time.Duration
+	StopTimeout  time.Duration
+}
+
+// Genesis is how a module's state moves in and out of a genesis file.
+// Modules added to the app keep their genesis state in
+// GenesisState.Modules under their name.
+type Genesis struct {
+	Init   module.GenesisHandler
+	Export module.GenesisHandler
+
+	// Prefixes are the state key prefixes the module owns. Export covers
+	// every key under them, and exporting a state with a key no module
+	// owns fails.
+	Prefixes []string
+}
+
+// Handler is a Connect service mounted on the HTTP server, as returned by
+// the generated New*Handler constructors.
+type Handler struct {
+	Path    string
+	Handler http.Handler
+}
+
+// handler wraps the result of a generated New*Handler constructor.
+func handler(path string, h http.Handler) []Handler {
+	return []Handler{{Path: path, Handler: h}}
+}
+
+// Env is what the app provides to the modules it builds.
+type Env struct {
+	Config     *config.Config
+	Logger     *zap.Logger
+	ChainStore *chainstore.ChainStore
+	LocalStore *localstore.LocalStore
+
+	// Chain submits and reads transactions. Its RPCs are available once the
+	// app is running.
+	Chain *chain.ChainService
+}
+
+// ModuleFactory builds a module from the app environment.
+type ModuleFactory func(env *Env) (*Module, error)
+
+// Option customizes the app built by NewApp.
+type Option func(*options)
+
+type options struct {
+	modules []ModuleFactory
+}
+
+// WithModules adds modules to the default Sonata modules. A module that
+// implements module.MsgModule or module.QueryModule has its transaction and
+// query handlers registered with core. One implementing module.BeginBlocker,
+// module.EndBlocker or module.CommitHookModule has its hooks run every block.
+func WithModules(factories ...ModuleFactory) Option {
+	return func(o *options) {
+		o.modules = append(o.modules, factories...)
+	}
+}
+
+// moduleByName indexes modules by name, rejecting duplicates.
+func moduleByName(modules []*Module) (map[string]*Module, error) {
+	byName := make(map[string]*Module, len(modules))
+	for _, m := range modules {
+		name := m.Name()
+		if _, exists := byName[name]; exists {
+			return nil, fmt.Errorf("module %q registered twice", name)
+		}
+		byName[name] = m
+	}
+	return byName, nil
+}
@@ -0,0 +1,232 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	abcitypes "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/crypto/ed25519"
+	cmtnm "github.com/cometbft/cometbft/node"
+	"github.com/sonata-labs/sonata/config"
+	"github.com/sonata-labs/sonata/core"
+	"github.com/sonata-labs/sonata/store/chainstore"
+	"github.com/sonata-labs/sonata/store/localstore"
+	"github.com/sonata-labs/sonata/x/chain"
+	"go.uber.org/zap"
+)
+
+// ReplayReport is the outcome of replaying a recording.
+type ReplayReport struct {
+	// Blocks is the number of blocks replayed and committed, Height the last
+	// of them.
+	Blocks int
+	Height int64
+
+	// Divergence is the first block whose result differs from the
+	// reference, nil if every replayed block matched.
+	Divergence *Divergence
+}
+
+// Divergence is a block whose replayed result differs from the reference.
+type Divergence struct {
+	Height int64
+	Reason string
+}
+
+// errStopReplay ends a replay early without an error.
+var errStopReplay = errors.New("stop replay")
+
+// replayReference is the result a replayed block is expected to produce.
+type replayReference struct {
+	initChain *abcitypes.InitChainResponse
+	blocks    map[int64]*abcitypes.FinalizeBlockResponse
+}
+
+// loadReference reads the responses recorded in the log at path.
+func loadReference(path string) (*replayReference, error) {
+	ref := &replayReference{blocks: make(map[int64]*abcitypes.FinalizeBlockResponse)}
+	err := core.ReadRecording(path, func(req *abcitypes.Request, resp *abcitypes.Response) error {
+		switch r := req.Value.(type) {
+		case *abcitypes.Request_InitChain:
+			ref.initChain = resp.GetInitChain()
+		case *abcitypes.Request_FinalizeBlock:
+			// a block executed again after a crash replaces the first attempt
+			ref.blocks[r.FinalizeBlock.Height] = resp.GetFinalizeBlock()
+		}
+		return nil
+	})
+	return ref, err
+}
+
+// Replay executes the blocks of the recording at recordingPath with this
+// binary's modules, on a fresh chain store, and compares each block's result
+// with the reference: the responses recorded in referencePath, or in the
+// recording itself if referencePath is empty. It stops at the first block
+// that diverges. The recording has to start at genesis.
+func Replay(ctx context.Context, cfg *config.Config, logger *zap.Logger, recordingPath, referencePath string, opts ...Option) (*ReplayReport, error) {
+	var ref *replayReference
+	if referencePath != "" {
+		var err error
+		if ref, err = loadReference(referencePath); err != nil {
+			return nil, fmt.Errorf("reading reference: %w", err)
+		}
+	}
+
+	dir, err := os.MkdirTemp("", "sonata-replay-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	// the replay runs on its own stores and is never recorded or snapshotted
+	// itself
+	sonataCfg := *cfg.Sonata
+	sonataCfg.Recorder = &config.RecorderConfig{}
+	sonataCfg.Snapshot = &config.SnapshotConfig{}
+	replayCfg := &config.Config{Sonata: &sonataCfg, CometBFT: cfg.CometBFT}
+
+	chainStore, err := chainstore.NewChainStore(filepath.Join(dir, "chainstore.db"))
+	if err != nil {
+		return nil, fmt.Errorf("opening chain store: %w", err)
+	}
+	defer chainStore.Close()
+
+	localStore, err := localstore.NewLocalStore(filepath.Join(dir, "local.db"))
+	if err != nil {
+		return nil, fmt.Errorf("opening local store: %w", err)
+	}
+	defer localStore.Close()
+
+	env := &Env{
+		Config:     replayCfg,
+		Logger:     logger,
+		ChainStore: chainStore,
+		LocalStore: localStore,
+		Chain:      chain.NewChainService(replayCfg, logger),
+	}
+
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	// the signer is only used to submit transactions, which a replay never does
+	modules, err := defaultModules(env, ed25519.GenPrivKey())
+	if err != nil {
+		return nil, err
+	}
+	for _, factory := range o.modules {
+		m, err := factory(env)
+		if err != nil {
+			return nil, err
+		}
+		modules = append(modules, m)
+	}
+
+	c, _, err := core.NewCore(replayCfg, logger, func(c *core.Core) (*cmtnm.Node, error) {
+		registerModules(c, modules)
+		return nil, nil
+	}, chainStore)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ReplayReport{}
+	diverged := func(height int64, format string, args ...any) error {
+		report.Divergence = &Divergence{Height: height, Reason: fmt.Sprintf(format, args...)}
+		return errStopReplay
+	}
+
+	var started bool
+	err = core.ReadRecording(recordingPath, func(req *abcitypes.Request, resp *abcitypes.Response) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		switch r := req.Value.(type) {
+		case *abcitypes.Request_InitChain:
+			// transactions are checked against the recorded chain id
+			sonataCfg.ChainID = r.InitChain.ChainId
+
+			got, err := c.InitChain(ctx, r.InitChain)
+			if err != nil {
+				return fmt.Errorf("replaying InitChain: %w", err)
+			}
+			started = true
+
+			want := resp.GetInitChain()
+			if ref != nil {
+				want = ref.initChain
+			}
+			if want != nil && !bytes.Equal(got.AppHash, want.AppHash) {
+				return diverged(r.InitChain.InitialHeight-1, "genesis app hash %X, reference %X", got.AppHash, want.AppHash)
+			}
+
+		case *abcitypes.Request_FinalizeBlock:
+			height := r.FinalizeBlock.Height
+			if !started {
+				return fmt.Errorf("recording starts at height %d instead of genesis", height)
+			}
+
+			got, err := c.FinalizeBlock(ctx, r.FinalizeBlock)
+			if err != nil {
+				return fmt.Errorf("replaying block %d: %w", height, err)
+			}
+
+			want := resp.GetFinalizeBlock()
+			if ref != nil {
+				if want = ref.blocks[height]; want == nil {
+					// nothing left to compare with
+					return errStopReplay
+				}
+			}
+			if reason := compareBlockResults(got, want); reason != "" {
+				return diverged(height, "%s", reason)
+			}
+
+		case *abcitypes.Request_Commit:
+			if _, err := c.Commit(ctx, r.Commit); err != nil {
+				return fmt.Errorf("committing replayed block: %w", err)
+			}
+			height, _, err := chainStore.GetLastBlock()
+			if err != nil {
+				return err
+			}
+			report.Blocks++
+			report.Height = height
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errStopReplay) {
+		return report, err
+	}
+	return report, nil
+}
+
+// compareBlockResults describes the first difference between a replayed
+// block's result and the reference, or returns "" if they match.
+func compareBlockResults(got, want *abcitypes.FinalizeBlockResponse) string {
+	if len(got.TxResults) != len(want.TxResults) {
+		return fmt.Sprintf("%d tx results, reference %d", len(got.TxResults), len(want.TxResults))
+	}
+	for i, g := range got.TxResults {
+		w := want.TxResults[i]
+		if g.Code != w.Code {
+			return fmt.Sprintf("tx %d: code %d (%s), reference %d (%s)", i, g.Code, g.Log, w.Code, w.Log)
+		}
+		if g.GasUsed != w.GasUsed {
+			return fmt.Sprintf("tx %d: gas used %d, reference %d", i, g.GasUsed, w.GasUsed)
+		}
+	}
+	if len(got.ValidatorUpdates) != len(want.ValidatorUpdates) {
+		return fmt.Sprintf("%d validator updates, reference %d", len(got.ValidatorUpdates), len(want.ValidatorUpdates))
+	}
+	if !bytes.Equal(got.AppHash, want.AppHash) {
+		return fmt.Sprintf("app hash %X, reference %X", got.AppHash, want.AppHash)
+	}
+	return ""
+}
@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/sonata-labs/sonata/app"
+	"github.com/sonata-labs/sonata/config"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func NewExportCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export the chain state as a genesis app_state",
+		Long: "Export the committed chain state as the app_state of a genesis file, " +
+			"to launch a new network from it. The node must be stopped.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			home, err := cmd.Flags().GetString("home")
+			if err != nil {
+				return err
+			}
+
+			cfg, err := config.ReadConfig(home)
+			if err != nil {
+				return err
+			}
+
+			state, height, err := app.ExportGenesis(cmd.Context(), cfg)
+			if err != nil {
+				return err
+			}
+
+			// protojson varies its whitespace between builds, so the output is
+			// re-indented to keep exports comparable
+			stateJSON, err := protojson.Marshal(state)
+			if err != nil {
+				return fmt.Errorf("encoding app state: %w", err)
+			}
+			var appState bytes.Buffer
+			if err := json.Indent(&appState, stateJSON, "", "  "); err != nil {
+				return fmt.Errorf("encoding app state: %w", err)
+			}
+			appState.WriteByte('\n')
+
+			output, _ := cmd.Flags().GetString("output")
+			if output == "" {
+				_, err = appState.WriteTo(os.Stdout)
+				return err
+			}
+			if err := os.WriteFile(output, appState.Bytes(), 0o644); err != nil {
+				return fmt.Errorf("write %s: %w", output, err)
+			}
+			fmt.Fprintf(os.Stderr, "exported state at height %d to %s\n", height, output)
+			return nil
+		},
+	}
+	cmd.Flags().String("output", "", "file to write the app_state to (default is stdout)")
+	return cmd
+}
@@ -7,11 +7,15 @@ import (
 
 	"github.com/cometbft/cometbft/crypto"
 	"github.com/sonata-labs/sonata/config"
+	accountv1 "github.com/sonata-labs/sonata/gen/account/v1"
+	chainv1 "github.com/sonata-labs/sonata/gen/chain/v1"
+	"github.com/sonata-labs/sonata/store/chainstore"
+	"github.com/sonata-labs/sonata/types/signing"
 	"github.com/spf13/cobra"
 )
 
 func NewInitCommand() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "init",
 		Short: "Initialize the Sonata node",
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -40,23 +44,44 @@ func NewInitCommand() *cobra.Command {
 			// create default configuration
 			cfg := config.DefaultConfig()
 			cfg.SetRoot(home)
+			chainID, _ := cmd.Flags().GetString("chain-id")
+			if chainID == "" {
+				chainID = config.NewChainID()
+			}
+			cfg.Sonata.ChainID = chainID
 
 			// generate node keys (CometBFT expects key files in config/, state in data/)
 			privValKeyFile := filepath.Join(configDir, "priv_validator_key.json")
 			privValStateFile := filepath.Join(dataDir, "priv_validator_state.json")
 			nodeKeyFile := filepath.Join(configDir, "node_key.json")
-			pv, _, err := config.GenerateNodeKeys(privValKeyFile, privValStateFile, nodeKeyFile)
+			pv, nodeKey, err := config.GenerateNodeKeys(privValKeyFile, privValStateFile, nodeKeyFile)
 			if err != nil {
 				return fmt.Errorf("generate node keys: %w", err)
 			}
 
-			// generate genesis file
+			// generate genesis file, with the chain id transactions are signed for
 			pubKey, err := pv.GetPubKey()
 			if err != nil {
 				return fmt.Errorf("get pub key: %w", err)
 			}
 
-			if err := config.GenerateGenesis(configDir, []crypto.PubKey{pubKey}); err != nil {
+			// the node's account pays for the transactions it submits, such as
+			// uploads, and may schedule upgrades of the network it starts
+			balance, _ := cmd.Flags().GetUint64("balance")
+			nodePubKey := nodeKey.PrivKey.PubKey()
+			nodeAddress := nodePubKey.Address().String()
+			params := chainstore.DefaultParams()
+			params.UpgradeAuthority = nodeAddress
+			appState := &chainv1.GenesisState{
+				Params: params,
+				Accounts: []*accountv1.Account{{
+					Address: nodeAddress,
+					PubKey:  signing.EncodePubKey(nodePubKey),
+					Balance: balance,
+				}},
+			}
+
+			if err := config.GenerateGenesis(configDir, cfg.Sonata.ChainID, []crypto.PubKey{pubKey}, appState); err != nil {
 				return fmt.Errorf("generate genesis file: %w", err)
 			}
 
@@ -68,4 +93,7 @@ func NewInitCommand() *cobra.Command {
 			return nil
 		},
 	}
+	cmd.Flags().Uint64("balance", 1_000_000_000_000, "genesis balance of the node's account")
+	cmd.Flags().String("chain-id", "", "chain id written to the genesis file and config (default sonata-<timestamp>)")
+	return cmd
 }
@@ -0,0 +1,58 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/sonata-labs/sonata/app"
+	"github.com/sonata-labs/sonata/config"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+func NewReplayCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "replay [recording]",
+		Short: "Re-execute a recording of the blocks a node executed",
+		Long: "Re-execute the blocks recorded by a node with [recorder] enabled on a fresh state, " +
+			"and report the first height whose result or app hash differs from the reference. " +
+			"The reference is the recording itself unless --reference names the recording of " +
+			"another node. The recording defaults to the one configured in the home directory.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			home, err := cmd.Flags().GetString("home")
+			if err != nil {
+				return err
+			}
+
+			cfg, err := config.ReadConfig(home)
+			if err != nil {
+				return err
+			}
+
+			recording := cfg.Sonata.Recorder.Path
+			if len(args) == 1 {
+				recording = args[0]
+			}
+			reference, _ := cmd.Flags().GetString("reference")
+
+			// module logs would drown the report
+			logger, err := zap.NewDevelopment(zap.IncreaseLevel(zap.WarnLevel))
+			if err != nil {
+				return err
+			}
+			defer logger.Sync()
+
+			report, err := app.Replay(cmd.Context(), cfg, logger, recording, reference)
+			if err != nil {
+				return err
+			}
+			if d := report.Divergence; d != nil {
+				return fmt.Errorf("diverged at height %d after %d matching blocks: %s", d.Height, report.Blocks, d.Reason)
+			}
+			fmt.Printf("replayed %d blocks up to height %d, no divergence\n", report.Blocks, report.Height)
+			return nil
+		},
+	}
+	cmd.Flags().String("reference", "", "recording of another node to compare against")
+	return cmd
+}
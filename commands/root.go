@@ -21,6 +21,8 @@ func NewRootCommand() *cobra.Command {
 
 	root.AddCommand(NewInitCommand())
 	root.AddCommand(NewStartCommand())
+	root.AddCommand(NewExportCommand())
+	root.AddCommand(NewReplayCommand())
 
 	return root
 }
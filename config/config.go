@@ -49,6 +49,9 @@ type SonataConfig struct {
 	Socket           *SocketConfig     `mapstructure:"socket" toml:"socket"`
 	ChainStore       *ChainStoreConfig `mapstructure:"chainstore" toml:"chainstore"`
 	LocalStore       *LocalStoreConfig `mapstructure:"localstore" toml:"localstore"`
+	Recorder         *RecorderConfig   `mapstructure:"recorder" toml:"recorder"`
+	Snapshot         *SnapshotConfig   `mapstructure:"snapshot" toml:"snapshot"`
+	Bootstrap        *BootstrapConfig  `mapstructure:"bootstrap" toml:"bootstrap"`
 }
 
 func DefaultSonataConfig() *SonataConfig {
@@ -60,6 +63,9 @@ func DefaultSonataConfig() *SonataConfig {
 		Socket:           DefaultSocketConfig(),
 		ChainStore:       DefaultChainStoreConfig(),
 		LocalStore:       DefaultLocalStoreConfig(),
+		Recorder:         DefaultRecorderConfig(),
+		Snapshot:         DefaultSnapshotConfig(),
+		Bootstrap:        DefaultBootstrapConfig(),
 	}
 }
 
@@ -73,6 +79,9 @@ func (c *SonataConfig) SetRoot(root string) {
 	c.Socket.SetRoot(root)
 	c.ChainStore.SetRoot(root)
 	c.LocalStore.SetRoot(root)
+	c.Recorder.SetRoot(root)
+	c.Snapshot.SetRoot(root)
+	c.Bootstrap.SetRoot(root)
 }
 
 type HTTPConfig struct {
@@ -112,12 +121,20 @@ func (c *SocketConfig) SetRoot(root string) {
 type ChainStoreConfig struct {
 	Root string `mapstructure:"root" toml:"root"`
 	Path string `mapstructure:"path" toml:"path"`
+
+	// KeepRecent is the number of recent heights whose state stays readable,
+	// 0 keeps every height. History older than that is pruned every
+	// PruneInterval blocks.
+	KeepRecent    int64 `mapstructure:"keep_recent" toml:"keep_recent"`
+	PruneInterval int64 `mapstructure:"prune_interval" toml:"prune_interval"`
 }
 
 func DefaultChainStoreConfig() *ChainStoreConfig {
 	return &ChainStoreConfig{
-		Root: DefaultHomeDirPath(),
-		Path: filepath.Join(DefaultHomeDirPath(), "data", "chainstore.db"),
+		Root:          DefaultHomeDirPath(),
+		Path:          filepath.Join(DefaultHomeDirPath(), "data", "chainstore.db"),
+		KeepRecent:    0,
+		PruneInterval: 10,
 	}
 }
 
@@ -143,6 +160,81 @@ func (c *LocalStoreConfig) SetRoot(root string) {
 	c.Path = filepath.Join(root, "data", "local.db")
 }
 
+// RecorderConfig controls the log of executed blocks used by sonata replay.
+type RecorderConfig struct {
+	Root    string `mapstructure:"root" toml:"root"`
+	Enabled bool   `mapstructure:"enabled" toml:"enabled"`
+	Path    string `mapstructure:"path" toml:"path"`
+}
+
+func DefaultRecorderConfig() *RecorderConfig {
+	return &RecorderConfig{
+		Root:    DefaultHomeDirPath(),
+		Enabled: false,
+		Path:    filepath.Join(DefaultHomeDirPath(), "data", "abci.rec"),
+	}
+}
+
+func (c *RecorderConfig) SetRoot(root string) {
+	c.Root = root
+	c.Path = filepath.Join(root, "data", "abci.rec")
+}
+
+// SnapshotConfig controls the chain store snapshots served to nodes joining
+// with state sync.
+type SnapshotConfig struct {
+	Root string `mapstructure:"root" toml:"root"`
+	Path string `mapstructure:"path" toml:"path"`
+
+	// Interval is the number of heights between snapshots, 0 takes none.
+	// KeepRecent is the number of most recent snapshots kept, 0 keeps all.
+	Interval   int64 `mapstructure:"interval" toml:"interval"`
+	KeepRecent int   `mapstructure:"keep_recent" toml:"keep_recent"`
+}
+
+func DefaultSnapshotConfig() *SnapshotConfig {
+	return &SnapshotConfig{
+		Root:       DefaultHomeDirPath(),
+		Path:       filepath.Join(DefaultHomeDirPath(), "data", "snapshots"),
+		Interval:   0,
+		KeepRecent: 2,
+	}
+}
+
+func (c *SnapshotConfig) SetRoot(root string) {
+	c.Root = root
+	c.Path = filepath.Join(root, "data", "snapshots")
+}
+
+// BootstrapConfig controls fetching the transcoded files this node serves
+// from peers, so a new or replacement node can serve files uploaded before it
+// joined.
+type BootstrapConfig struct {
+	Root    string `mapstructure:"root" toml:"root"`
+	Enabled bool   `mapstructure:"enabled" toml:"enabled"`
+
+	// Peers are the Sonata HTTP API URLs files are fetched from, tried in
+	// order.
+	Peers []string `mapstructure:"peers" toml:"peers"`
+
+	// ReplicateAll fetches every upload on chain, instead of only the uploads
+	// this node's account transcoded.
+	ReplicateAll bool `mapstructure:"replicate_all" toml:"replicate_all"`
+}
+
+func DefaultBootstrapConfig() *BootstrapConfig {
+	return &BootstrapConfig{
+		Root:         DefaultHomeDirPath(),
+		Enabled:      false,
+		Peers:        []string{},
+		ReplicateAll: false,
+	}
+}
+
+func (c *BootstrapConfig) SetRoot(root string) {
+	c.Root = root
+}
+
 // SaveAs writes the SonataConfig to the specified file path as TOML.
 func (c *SonataConfig) SaveAs(filePath string) error {
 	data, err := toml.Marshal(c)
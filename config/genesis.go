@@ -1,16 +1,26 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
 	"path/filepath"
 	"time"
 
 	"github.com/cometbft/cometbft/crypto"
 	cmttypes "github.com/cometbft/cometbft/types"
+	chainv1 "github.com/sonata-labs/sonata/gen/chain/v1"
+	"google.golang.org/protobuf/encoding/protojson"
 )
 
-// generates the genesis file for the Sonata chain
-func GenerateGenesis(configDir string, validatorKeys []crypto.PubKey) error {
+// NewChainID returns a chain id unique to the time it is generated, so
+// separately initialized networks do not accept each other's transactions.
+func NewChainID() string {
+	return fmt.Sprintf("sonata-%s", time.Now().Format("20060102150405"))
+}
+
+// generates the genesis file for the Sonata chain, with appState as the
+// state the chain starts from
+func GenerateGenesis(configDir string, chainID string, validatorKeys []crypto.PubKey, appState *chainv1.GenesisState) error {
 	validators := make([]cmttypes.GenesisValidator, len(validatorKeys))
 	for i, validator := range validatorKeys {
 		validators[i] = cmttypes.GenesisValidator{
@@ -21,11 +31,16 @@ func GenerateGenesis(configDir string, validatorKeys []crypto.PubKey) error {
 		}
 	}
 
-	chainID := fmt.Sprintf("sonata-%s", time.Now().Format("20060102150405"))
+	appStateJSON, err := protojson.MarshalOptions{Indent: "  "}.Marshal(appState)
+	if err != nil {
+		return fmt.Errorf("encoding app state: %w", err)
+	}
+
 	genDoc := cmttypes.GenesisDoc{
 		ChainID:     chainID,
 		GenesisTime: time.Now(),
 		Validators:  validators,
+		AppState:    json.RawMessage(appStateJSON),
 	}
 
 	if err := genDoc.ValidateAndComplete(); err != nil {
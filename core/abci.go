@@ -2,11 +2,17 @@ package core
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"reflect"
 	"sync"
+	"time"
 
 	abcitypes "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/crypto/tmhash"
 	"github.com/cometbft/cometbft/node"
 	"github.com/sonata-labs/sonata/config"
+	chainv1 "github.com/sonata-labs/sonata/gen/chain/v1"
 	"github.com/sonata-labs/sonata/store/chainstore"
 	"github.com/sonata-labs/sonata/types/module"
 	"go.uber.org/zap"
@@ -33,10 +39,19 @@ const (
 )
 
 type Core struct {
-	config  *config.Config
-	modules map[Callback][]module.Module
-	node    *node.Node
-	logger  *zap.SugaredLogger
+	config          *config.Config
+	modules         map[Callback][]module.Module
+	msgHandlers     map[reflect.Type]msgRoute
+	anteHandlers    []module.AnteHandler
+	proposalChecks  []module.AnteHandler
+	queryHandlers   map[string]module.QueryHandler
+	genesisHandlers []genesisRoute
+	upgradeHandlers map[string]module.UpgradeHandler
+	beginBlockers   []module.BeginBlocker
+	endBlockers     []module.EndBlocker
+	commitHooks     []commitHookRoute
+	node            *node.Node
+	logger          *zap.SugaredLogger
 
 	ready        chan struct{}
 	startupDeps  []<-chan struct{}
@@ -45,16 +60,44 @@ type Core struct {
 
 	chainStore *chainstore.ChainStore
 	batch      *chainstore.ChainStore
+
+	// checkState accumulates the ante writes of transactions admitted to the
+	// mempool since the last commit, so a sender's pending nonces are known
+	checkState *chainstore.ChainStore
+
+	// height and app hash of the block being finalized, persisted on Commit
+	height  int64
+	appHash []byte
+
+	// recorder logs executed blocks for sonata replay, if enabled
+	recorder *Recorder
+
+	// typed events of the block's successful transactions, handed to the
+	// commit hooks
+	txEvents []*chainv1.TransactionEvent
 }
 
 func NewCore(config *config.Config, logger *zap.Logger, init func(c *Core) (*node.Node, error), chainStore *chainstore.ChainStore) (*Core, *node.Node, error) {
 	c := &Core{
-		config:     config,
-		modules:    make(map[Callback][]module.Module),
-		logger:     logger.Named("core").Sugar(),
-		ready:      make(chan struct{}),
-		stopped:    make(chan struct{}),
-		chainStore: chainStore,
+		config:          config,
+		modules:         make(map[Callback][]module.Module),
+		msgHandlers:     make(map[reflect.Type]msgRoute),
+		queryHandlers:   make(map[string]module.QueryHandler),
+		upgradeHandlers: make(map[string]module.UpgradeHandler),
+		logger:          logger.Named("core").Sugar(),
+		ready:           make(chan struct{}),
+		stopped:         make(chan struct{}),
+		chainStore:      chainStore,
+		checkState:      chainStore.Branch(),
+	}
+
+	// the recorder has to be open before the handshake executes blocks
+	if config.Sonata.Recorder.Enabled {
+		recorder, err := NewRecorder(config.Sonata.Recorder.Path)
+		if err != nil {
+			return nil, nil, err
+		}
+		c.recorder = recorder
 	}
 
 	node, err := init(c)
@@ -143,6 +186,9 @@ func (c *Core) Stop() error {
 	if c.node != nil {
 		err = c.node.Stop()
 	}
+	if c.recorder != nil {
+		err = errors.Join(err, c.recorder.Close())
+	}
 	close(c.stopped)
 	return err
 }
@@ -150,21 +196,33 @@ func (c *Core) Stop() error {
 // Info/Query Connection
 
 func (c *Core) Info(ctx context.Context, req *abcitypes.InfoRequest) (*abcitypes.InfoResponse, error) {
-	var lastResp *abcitypes.InfoResponse
+	lastResp := &abcitypes.InfoResponse{}
 	for _, mod := range c.modules[Info] {
 		resp, err := mod.Info(ctx, req)
 		if err != nil {
 			return nil, err
 		}
-		lastResp = resp
+		if resp != nil {
+			lastResp = resp
+		}
 	}
-	if lastResp == nil {
-		return &abcitypes.InfoResponse{}, nil
+
+	// the handshake replays only the blocks after what the chain store has committed
+	height, appHash, err := c.chainStore.GetLastBlock()
+	if err != nil {
+		return nil, fmt.Errorf("reading last block: %w", err)
 	}
+	lastResp.LastBlockHeight = height
+	lastResp.LastBlockAppHash = appHash
+
 	return lastResp, nil
 }
 
 func (c *Core) Query(ctx context.Context, req *abcitypes.QueryRequest) (*abcitypes.QueryResponse, error) {
+	if handler, args, ok := c.routeQuery(req.Path); ok {
+		return c.query(ctx, req, handler, args)
+	}
+
 	for _, mod := range c.modules[Query] {
 		resp, err := mod.Query(ctx, req)
 		if err != nil {
@@ -181,6 +239,60 @@ func (c *Core) Query(ctx context.Context, req *abcitypes.QueryRequest) (*abcityp
 // Mempool Connection
 
 func (c *Core) CheckTx(ctx context.Context, req *abcitypes.CheckTxRequest) (*abcitypes.CheckTxResponse, error) {
+	// reject anything that could never be executed before it reaches the mempool
+	signedTx, route, err := c.decodeTx(req.Tx)
+	if err != nil {
+		return &abcitypes.CheckTxResponse{
+			Code: 1,
+			Info: "invalid transaction",
+			Log:  err.Error(),
+		}, nil
+	}
+
+	params, err := c.checkState.GetParams()
+	if err != nil {
+		return nil, err
+	}
+	if params.MaxTxBytes > 0 && uint64(len(req.Tx)) > params.MaxTxBytes {
+		return &abcitypes.CheckTxResponse{
+			Code: 1,
+			Info: "invalid transaction",
+			Log:  fmt.Sprintf("tx is %d bytes, max %d", len(req.Tx), params.MaxTxBytes),
+		}, nil
+	}
+
+	gasWanted := int64(signedTx.Transaction.Header.GasLimit)
+	meter, err := intrinsicGas(signedTx, route, len(req.Tx))
+	if err != nil {
+		return &abcitypes.CheckTxResponse{
+			Code:      1,
+			Info:      "transaction rejected",
+			Log:       err.Error(),
+			GasWanted: gasWanted,
+		}, nil
+	}
+
+	// ante checks run on top of the transactions already in the mempool and
+	// keep their writes once admitted, e.g. the sender's consumed nonce
+	tx := &module.Tx{
+		Hash:     tmhash.Sum(req.Tx),
+		Height:   c.height,
+		Time:     time.Now(),
+		Signed:   signedTx,
+		Store:    c.checkState.Branch(),
+		Gas:      meter,
+		Simulate: true,
+	}
+	tx.Store.SetGasMeter(meter)
+	if err := c.runAnteHandlers(ctx, tx); err != nil {
+		return &abcitypes.CheckTxResponse{
+			Code:      1,
+			Info:      "transaction rejected",
+			Log:       err.Error(),
+			GasWanted: gasWanted,
+		}, nil
+	}
+
 	for _, mod := range c.modules[CheckTx] {
 		resp, err := mod.CheckTx(ctx, req)
 		if err != nil {
@@ -191,34 +303,40 @@ func (c *Core) CheckTx(ctx context.Context, req *abcitypes.CheckTxRequest) (*abc
 			return resp, nil
 		}
 	}
-	return &abcitypes.CheckTxResponse{Code: 0}, nil
+
+	if err := tx.Store.Write(); err != nil {
+		return nil, err
+	}
+	return &abcitypes.CheckTxResponse{Code: 0, GasWanted: gasWanted}, nil
 }
 
 // Consensus Connection
 
 func (c *Core) InitChain(ctx context.Context, req *abcitypes.InitChainRequest) (*abcitypes.InitChainResponse, error) {
 	var validators []abcitypes.ValidatorUpdate
-	var appHash []byte
+
+	// the first block's header commits to the imported genesis state
+	appHash, err := c.initGenesis(ctx, req)
+	if err != nil {
+		return nil, err
+	}
 
 	for _, mod := range c.modules[InitChain] {
 		resp, err := mod.InitChain(ctx, req)
 		if err != nil {
 			return nil, err
 		}
-		if resp != nil {
-			if len(resp.Validators) > 0 {
-				validators = resp.Validators
-			}
-			if len(resp.AppHash) > 0 {
-				appHash = resp.AppHash
-			}
+		if resp != nil && len(resp.Validators) > 0 {
+			validators = resp.Validators
 		}
 	}
 
-	return &abcitypes.InitChainResponse{
+	resp := &abcitypes.InitChainResponse{
 		Validators: validators,
 		AppHash:    appHash,
-	}, nil
+	}
+	c.record(abcitypes.ToInitChainRequest(req), abcitypes.ToInitChainResponse(resp))
+	return resp, nil
 }
 
 func (c *Core) PrepareProposal(ctx context.Context, req *abcitypes.PrepareProposalRequest) (*abcitypes.PrepareProposalResponse, error) {
@@ -234,10 +352,15 @@ func (c *Core) PrepareProposal(ctx context.Context, req *abcitypes.PreparePropos
 		}
 	}
 
-	return &abcitypes.PrepareProposalResponse{Txs: txs}, nil
+	return &abcitypes.PrepareProposalResponse{Txs: c.packProposal(ctx, req, txs)}, nil
 }
 
 func (c *Core) ProcessProposal(ctx context.Context, req *abcitypes.ProcessProposalRequest) (*abcitypes.ProcessProposalResponse, error) {
+	if err := c.verifyProposal(ctx, req); err != nil {
+		c.logger.Warnw("rejecting proposal", "height", req.Height, "error", err)
+		return &abcitypes.ProcessProposalResponse{Status: abcitypes.PROCESS_PROPOSAL_STATUS_REJECT}, nil
+	}
+
 	for _, mod := range c.modules[ProcessProposal] {
 		resp, err := mod.ProcessProposal(ctx, req)
 		if err != nil {
@@ -252,59 +375,135 @@ func (c *Core) ProcessProposal(ctx context.Context, req *abcitypes.ProcessPropos
 }
 
 func (c *Core) FinalizeBlock(ctx context.Context, req *abcitypes.FinalizeBlockRequest) (*abcitypes.FinalizeBlockResponse, error) {
-	var validators []abcitypes.ValidatorUpdate
-	var events []abcitypes.Event
-	var appHash []byte
+	var result blockResult
 	c.batch = c.chainStore.Batch()
+	c.txEvents = nil
+
+	header := module.BlockHeader{
+		Height:          req.Height,
+		Time:            req.Time,
+		Hash:            req.Hash,
+		ProposerAddress: req.ProposerAddress,
+	}
+	ctx = module.ContextWithBlockHeader(ctx, header)
 
-	// Initialize tx results - one per transaction in the block
+	// a scheduled upgrade migrates state before the block's transactions, or
+	// halts a binary that does not know it
+	if err := c.applyUpgrade(ctx, req.Height); err != nil {
+		return nil, err
+	}
+
+	for _, b := range c.beginBlockers {
+		if err := c.runBlockHook(b, "BeginBlock", &result, func() (*module.BlockResult, error) {
+			return b.BeginBlock(ctx, header)
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	// one result per transaction in the block
 	txResults := make([]*abcitypes.ExecTxResult, len(req.Txs))
-	for i := range txResults {
-		txResults[i] = &abcitypes.ExecTxResult{Code: 0}
+	for i, tx := range req.Txs {
+		txResult, err := c.deliverTx(ctx, req, tx)
+		if err != nil {
+			return nil, fmt.Errorf("delivering tx %d: %w", i, err)
+		}
+		txResults[i] = txResult
 	}
 
 	for _, mod := range c.modules[FinalizeBlock] {
-		// set module chain store to the core controlled batch
-		mod.SetChainStoreBatch(c.batch)
-		resp, err := mod.FinalizeBlock(ctx, req)
-		if err != nil {
-			// Convert module error to failed tx results instead of failing consensus
-			c.logger.Errorw("module error during FinalizeBlock", "module", mod.Name(), "error", err)
-			for i := range txResults {
-				if txResults[i].Code == 0 {
-					txResults[i] = &abcitypes.ExecTxResult{
-						Code: 1,
-						Log:  err.Error(),
-					}
-				}
+		if err := c.runBlockHook(mod, "FinalizeBlock", &result, func() (*module.BlockResult, error) {
+			resp, err := mod.FinalizeBlock(ctx, req)
+			if err != nil || resp == nil {
+				return nil, err
 			}
-			continue
+			return &module.BlockResult{Events: resp.Events, ValidatorUpdates: resp.ValidatorUpdates}, nil
+		}); err != nil {
+			return nil, err
 		}
-		if resp != nil {
-			// Merge tx results from modules (if they provide any)
-			for i, result := range resp.TxResults {
-				if i < len(txResults) && result != nil {
-					txResults[i] = result
-				}
-			}
-			if len(resp.ValidatorUpdates) > 0 {
-				validators = resp.ValidatorUpdates
-			}
-			if len(resp.Events) > 0 {
-				events = append(events, resp.Events...)
-			}
-			if len(resp.AppHash) > 0 {
-				appHash = resp.AppHash
-			}
+	}
+
+	for _, e := range c.endBlockers {
+		if err := c.runBlockHook(e, "EndBlock", &result, func() (*module.BlockResult, error) {
+			return e.EndBlock(ctx)
+		}); err != nil {
+			return nil, err
 		}
 	}
 
-	return &abcitypes.FinalizeBlockResponse{
+	// commit to the state written by this block so diverging validators
+	// disagree on the next block's app hash
+	appHash, err := c.batch.ComputeAppHash(req.Height)
+	if err != nil {
+		return nil, fmt.Errorf("computing app hash: %w", err)
+	}
+	c.height = req.Height
+	c.appHash = appHash
+
+	resp := &abcitypes.FinalizeBlockResponse{
 		TxResults:        txResults,
-		ValidatorUpdates: validators,
-		Events:           events,
+		ValidatorUpdates: result.validators,
+		Events:           result.events,
 		AppHash:          appHash,
-	}, nil
+	}
+	c.record(abcitypes.ToFinalizeBlockRequest(req), abcitypes.ToFinalizeBlockResponse(resp))
+	return resp, nil
+}
+
+// deliverTx decodes a transaction, runs the ante handlers and then its body
+// handler, each against its own branch of the block batch. A failing body is
+// rolled back on its own and reported in its result; only storage errors are
+// returned.
+func (c *Core) deliverTx(ctx context.Context, req *abcitypes.FinalizeBlockRequest, txBytes []byte) (*abcitypes.ExecTxResult, error) {
+	signedTx, route, err := c.decodeTx(txBytes)
+	if err != nil {
+		return &abcitypes.ExecTxResult{Code: 1, Log: err.Error()}, nil
+	}
+
+	gasWanted := int64(signedTx.Transaction.Header.GasLimit)
+	meter, err := intrinsicGas(signedTx, route, len(txBytes))
+	if err != nil {
+		return &abcitypes.ExecTxResult{Code: 1, Log: err.Error(), GasWanted: gasWanted, GasUsed: int64(meter.Used())}, nil
+	}
+
+	tx := &module.Tx{
+		Hash:   tmhash.Sum(txBytes),
+		Height: req.Height,
+		Time:   req.Time,
+		Signed: signedTx,
+		Store:  c.batch.Branch(),
+		Gas:    meter,
+	}
+	tx.Store.SetGasMeter(meter)
+	if err := c.runAnteHandlers(ctx, tx); err != nil {
+		c.logger.Warnw("tx rejected", "hash", tx.HashHex(), "error", err)
+		return &abcitypes.ExecTxResult{Code: 1, Log: err.Error(), GasWanted: gasWanted, GasUsed: int64(meter.Used())}, nil
+	}
+
+	// ante writes such as the consumed nonce and the fee stick even if the
+	// body fails
+	if err := tx.Store.Write(); err != nil {
+		return nil, err
+	}
+
+	branch := c.batch.Branch()
+	branch.SetGasMeter(meter)
+	tx.Store = branch
+	err = route.handler(ctx, tx)
+	if err == nil && meter.IsExceeded() {
+		// the handler swallowed an out of gas error
+		err = chainstore.ErrOutOfGas
+	}
+	if err != nil {
+		c.logger.Warnw("tx failed", "hash", tx.HashHex(), "error", err)
+		return &abcitypes.ExecTxResult{Code: 1, Log: err.Error(), GasWanted: gasWanted, GasUsed: int64(meter.Used())}, nil
+	}
+
+	if err := branch.Write(); err != nil {
+		return nil, err
+	}
+	c.txEvents = append(c.txEvents, tx.TransactionEvents()...)
+	return &abcitypes.ExecTxResult{Code: 0, GasWanted: gasWanted, GasUsed: int64(meter.Used()), Events: tx.Events()}, nil
 }
 
 // Vote Extensions
@@ -356,12 +555,51 @@ func (c *Core) Commit(ctx context.Context, req *abcitypes.CommitRequest) (*abcit
 		mod.SetChainStoreBatch(nil)
 	}
 
+	// record the block in the same batch so state and height land atomically
+	if err := c.batch.StoreLastBlock(c.height, c.appHash); err != nil {
+		return nil, err
+	}
+
+	// history older than the retained heights goes with the same commit
+	if err := c.pruneHistory(); err != nil {
+		return nil, err
+	}
+
 	// commit the batch from all modules
 	if err := c.batch.Commit(); err != nil {
 		return nil, err
 	}
 
-	return &abcitypes.CommitResponse{RetainHeight: retainHeight}, nil
+	// the mempool is rechecked against the new state after commit
+	c.checkState = c.chainStore.Branch()
+
+	// side effects only happen once the block can no longer be lost
+	c.runCommitHooks(ctx, &module.CommittedBlock{
+		Height:  c.height,
+		AppHash: c.appHash,
+		Events:  c.txEvents,
+	})
+	c.txEvents = nil
+
+	resp := &abcitypes.CommitResponse{RetainHeight: retainHeight}
+	c.record(abcitypes.ToCommitRequest(), abcitypes.ToCommitResponse(resp))
+	return resp, nil
+}
+
+// pruneHistory drops the state history older than the configured number of
+// recent heights, every prune interval.
+func (c *Core) pruneHistory() error {
+	cfg := c.config.Sonata.ChainStore
+	if cfg.KeepRecent <= 0 || c.height <= cfg.KeepRecent {
+		return nil
+	}
+	if cfg.PruneInterval > 1 && c.height%cfg.PruneInterval != 0 {
+		return nil
+	}
+	if err := c.batch.PruneHistory(c.height - cfg.KeepRecent + 1); err != nil {
+		return fmt.Errorf("pruning state history: %w", err)
+	}
+	return nil
 }
 
 // State Sync
@@ -0,0 +1,51 @@
+package core
+
+import (
+	"fmt"
+
+	abcitypes "github.com/cometbft/cometbft/abci/types"
+	"github.com/sonata-labs/sonata/types/module"
+)
+
+// RegisterBeginBlocker adds a module hook run at the start of every block.
+// Hooks run in registration order.
+func (c *Core) RegisterBeginBlocker(b module.BeginBlocker) {
+	c.beginBlockers = append(c.beginBlockers, b)
+}
+
+// RegisterEndBlocker adds a module hook run at the end of every block. Hooks
+// run in registration order.
+func (c *Core) RegisterEndBlocker(e module.EndBlocker) {
+	c.endBlockers = append(c.endBlockers, e)
+}
+
+// blockResult collects the events and validator updates of a block.
+type blockResult struct {
+	events     []abcitypes.Event
+	validators []abcitypes.ValidatorUpdate
+}
+
+func (r *blockResult) add(events []abcitypes.Event, validators []abcitypes.ValidatorUpdate) {
+	r.events = append(r.events, events...)
+	r.validators = append(r.validators, validators...)
+}
+
+// runBlockHook runs a block hook of mod on its own branch of the block batch.
+// A failing hook fails the block, which halts the node: skipping the hook
+// would commit state other validators did not, and writing part of it would
+// commit state no validator agreed on.
+func (c *Core) runBlockHook(mod module.Module, phase string, result *blockResult, hook func() (*module.BlockResult, error)) error {
+	branch := c.batch.Branch()
+	mod.SetChainStoreBatch(branch)
+	res, err := hook()
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", mod.Name(), phase, err)
+	}
+	if err := branch.Write(); err != nil {
+		return fmt.Errorf("writing %s %s state: %w", mod.Name(), phase, err)
+	}
+	if res != nil {
+		result.add(res.Events, res.ValidatorUpdates)
+	}
+	return nil
+}
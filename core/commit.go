@@ -0,0 +1,43 @@
+package core
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sonata-labs/sonata/types/module"
+)
+
+var _ module.CommitHookRouter = (*Core)(nil)
+
+// commitHookRoute is a commit hook and the module that registered it.
+type commitHookRoute struct {
+	name string
+	hook module.CommitHook
+}
+
+// RegisterCommitHook adds a hook run after every block commit. Hooks run in
+// registration order.
+func (c *Core) RegisterCommitHook(name string, hook module.CommitHook) {
+	c.commitHooks = append(c.commitHooks, commitHookRoute{name: name, hook: hook})
+}
+
+// runCommitHooks runs the commit hooks for a block once its batch has been
+// committed. Failures are logged, the block stays committed.
+func (c *Core) runCommitHooks(ctx context.Context, block *module.CommittedBlock) {
+	for _, route := range c.commitHooks {
+		if err := runCommitHook(ctx, route.hook, block); err != nil {
+			c.logger.Errorw("commit hook failed", "module", route.name, "height", block.Height, "error", err)
+		}
+	}
+}
+
+// runCommitHook runs hook, turning a panic into an error since the block it
+// runs for is already committed.
+func runCommitHook(ctx context.Context, hook module.CommitHook, block *module.CommittedBlock) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panicked: %v", r)
+		}
+	}()
+	return hook(ctx, block)
+}
@@ -0,0 +1,94 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	abcitypes "github.com/cometbft/cometbft/abci/types"
+	chainv1 "github.com/sonata-labs/sonata/gen/chain/v1"
+	"github.com/sonata-labs/sonata/types/module"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+var _ module.GenesisRouter = (*Core)(nil)
+
+// genesisRoute is a module's genesis import.
+type genesisRoute struct {
+	name    string
+	handler module.GenesisHandler
+}
+
+// RegisterGenesisHandler adds a module's genesis import, run in InitChain
+// after the modules registered before it.
+func (c *Core) RegisterGenesisHandler(name string, handler module.GenesisHandler) {
+	for _, route := range c.genesisHandlers {
+		if route.name == name {
+			panic(fmt.Sprintf("genesis handler already registered for %s", name))
+		}
+	}
+	c.genesisHandlers = append(c.genesisHandlers, genesisRoute{name: name, handler: handler})
+}
+
+// DecodeGenesisState parses the app_state of a genesis file. An empty
+// app_state decodes to an empty state.
+func DecodeGenesisState(appState []byte) (*chainv1.GenesisState, error) {
+	state := &chainv1.GenesisState{}
+	if len(appState) == 0 || string(appState) == "null" {
+		return state, nil
+	}
+	if err := protojson.Unmarshal(appState, state); err != nil {
+		return nil, fmt.Errorf("decoding genesis app state: %w", err)
+	}
+	return state, nil
+}
+
+// initGenesis imports the genesis app state and commits it as the state
+// tree version before the initial height, returning its app hash. A node
+// stopped before its first block has already committed genesis, and gets
+// the same hash back without importing again.
+func (c *Core) initGenesis(ctx context.Context, req *abcitypes.InitChainRequest) ([]byte, error) {
+	genesisHeight := req.InitialHeight - 1
+	if appHash, ok, err := c.chainStore.AppHashAt(genesisHeight); err != nil {
+		return nil, err
+	} else if ok {
+		c.logger.Infow("genesis state already imported", "height", genesisHeight)
+		return appHash, nil
+	}
+
+	state, err := DecodeGenesisState(req.AppStateBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	// the app state of a module this node does not run would be left out
+	for name := range state.Modules {
+		if !slices.ContainsFunc(c.genesisHandlers, func(route genesisRoute) bool { return route.name == name }) {
+			return nil, fmt.Errorf("genesis app state for module %s, which this node does not run", name)
+		}
+	}
+
+	batch := c.chainStore.Batch()
+	for _, route := range c.genesisHandlers {
+		if err := route.handler(ctx, batch, state); err != nil {
+			return nil, fmt.Errorf("importing %s genesis: %w", route.name, err)
+		}
+	}
+
+	if err := batch.InitSchema(); err != nil {
+		return nil, fmt.Errorf("recording schema version: %w", err)
+	}
+
+	appHash, err := batch.ComputeAppHash(genesisHeight)
+	if err != nil {
+		return nil, fmt.Errorf("computing genesis app hash: %w", err)
+	}
+	if err := batch.Commit(); err != nil {
+		return nil, err
+	}
+	c.checkState = c.chainStore.Branch()
+
+	c.logger.Infow("imported genesis state",
+		"accounts", len(state.Accounts), "uploads", len(state.Uploads), "ddex_accounts", len(state.Ddex))
+	return appHash, nil
+}
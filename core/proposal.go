@@ -0,0 +1,192 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	abcitypes "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/crypto/tmhash"
+	cmttypes "github.com/cometbft/cometbft/types"
+	chainv1 "github.com/sonata-labs/sonata/gen/chain/v1"
+	"github.com/sonata-labs/sonata/store/chainstore"
+	"github.com/sonata-labs/sonata/types/module"
+)
+
+// proposalTx is a mempool transaction considered for a block proposal.
+type proposalTx struct {
+	bytes  []byte
+	signed *chainv1.SignedTransaction
+	route  msgRoute
+
+	// position in the mempool, which keeps a sender's nonces in order
+	index int
+}
+
+func (p *proposalTx) header() *chainv1.TransactionHeader {
+	return p.signed.Transaction.Header
+}
+
+// packProposal selects the transactions for a block proposal. Senders are
+// served by the gas price of their next transaction, so higher fees get in
+// first while each sender's transactions keep their nonce order. The block
+// is filled up to MaxTxBytes and the max_block_gas param, leaving out
+// transactions larger than the max_tx_bytes param.
+//
+// Transactions that no longer pass the ante handlers against the state the
+// block builds on are dropped, along with the rest of their sender's
+// transactions, which would then have a nonce gap.
+func (c *Core) packProposal(ctx context.Context, req *abcitypes.PrepareProposalRequest, candidates [][]byte) [][]byte {
+	state := c.chainStore.Branch()
+	params, err := state.GetParams()
+	if err != nil {
+		c.logger.Errorw("reading chain params, proposing an empty block", "error", err)
+		return nil
+	}
+	maxGas := params.MaxBlockGas
+
+	queues := make(map[string][]*proposalTx)
+	var senders []string
+	for i, txBytes := range candidates {
+		if params.MaxTxBytes > 0 && uint64(len(txBytes)) > params.MaxTxBytes {
+			continue
+		}
+		signedTx, route, err := c.decodeTx(txBytes)
+		if err != nil {
+			c.logger.Debugw("dropping undecodable tx from proposal", "error", err)
+			continue
+		}
+		sender := signedTx.Transaction.Header.Sender
+		if _, ok := queues[sender]; !ok {
+			senders = append(senders, sender)
+		}
+		queues[sender] = append(queues[sender], &proposalTx{bytes: txBytes, signed: signedTx, route: route, index: i})
+	}
+
+	var txs [][]byte
+	var size int64
+	var gas uint64
+	for {
+		next := nextByPriority(queues, senders)
+		if next == nil {
+			break
+		}
+		sender := next.header().Sender
+
+		// a sender's later transactions cannot skip ahead, but other
+		// senders' transactions may still fit
+		txSize := cmttypes.ComputeProtoSizeForTxs([]cmttypes.Tx{next.bytes})
+		if size+txSize > req.MaxTxBytes || (maxGas > 0 && next.header().GasLimit > maxGas-gas) {
+			delete(queues, sender)
+			continue
+		}
+
+		if err := c.simulateAnte(ctx, state, req.Height, req.Time, next); err != nil {
+			c.logger.Debugw("dropping tx from proposal", "sender", sender, "error", err)
+			delete(queues, sender)
+			continue
+		}
+
+		txs = append(txs, next.bytes)
+		size += txSize
+		gas += next.header().GasLimit
+		queues[sender] = queues[sender][1:]
+	}
+	return txs
+}
+
+// nextByPriority returns the pending transaction with the highest gas price
+// among the head of each sender's queue, preferring the one that entered the
+// mempool first on ties.
+func nextByPriority(queues map[string][]*proposalTx, senders []string) *proposalTx {
+	var next *proposalTx
+	for _, sender := range senders {
+		queue := queues[sender]
+		if len(queue) == 0 {
+			continue
+		}
+		head := queue[0]
+		if next == nil ||
+			head.header().GasPrice > next.header().GasPrice ||
+			(head.header().GasPrice == next.header().GasPrice && head.index < next.index) {
+			next = head
+		}
+	}
+	return next
+}
+
+// simulateAnte runs the ante handlers for a proposed transaction on top of
+// state, keeping their writes so the next transaction sees the sender's
+// updated nonce and balance.
+func (c *Core) simulateAnte(ctx context.Context, state *chainstore.ChainStore, height int64, blockTime time.Time, p *proposalTx) error {
+	meter, err := intrinsicGas(p.signed, p.route, len(p.bytes))
+	if err != nil {
+		return err
+	}
+
+	tx := &module.Tx{
+		Hash:     tmhash.Sum(p.bytes),
+		Height:   height,
+		Time:     blockTime,
+		Signed:   p.signed,
+		Store:    state.Branch(),
+		Gas:      meter,
+		Simulate: true,
+	}
+	tx.Store.SetGasMeter(meter)
+	if err := c.runAnteHandlers(ctx, tx); err != nil {
+		return err
+	}
+	if err := tx.Store.Write(); err != nil {
+		return fmt.Errorf("writing simulated state: %w", err)
+	}
+	return nil
+}
+
+// verifyProposal refuses a block carrying transactions that could never be
+// executed: undecodable, larger than the max_tx_bytes param, or failing a
+// proposal check such as a bad signature or another chain's id, or together
+// asking for more gas than the max_block_gas param. The checks depend only on
+// the proposal, the chain params and the last committed state, so every
+// honest validator reaches the same verdict. Transactions that fail against
+// the state the block builds up, such as a stale nonce or a fee the sender
+// cannot pay, are left to fail on their own when the block is executed.
+func (c *Core) verifyProposal(ctx context.Context, req *abcitypes.ProcessProposalRequest) error {
+	params, err := c.chainStore.GetParams()
+	if err != nil {
+		return err
+	}
+	maxGas := params.MaxBlockGas
+
+	var gas uint64
+	for i, txBytes := range req.Txs {
+		if params.MaxTxBytes > 0 && uint64(len(txBytes)) > params.MaxTxBytes {
+			return fmt.Errorf("tx %d is %d bytes, max %d", i, len(txBytes), params.MaxTxBytes)
+		}
+
+		signedTx, _, err := c.decodeTx(txBytes)
+		if err != nil {
+			return fmt.Errorf("tx %d: %w", i, err)
+		}
+
+		gasLimit := signedTx.Transaction.Header.GasLimit
+		if maxGas > 0 && gasLimit > maxGas-gas {
+			return fmt.Errorf("tx %d exceeds the block gas limit %d", i, maxGas)
+		}
+		gas += gasLimit
+
+		tx := &module.Tx{
+			Hash:   tmhash.Sum(txBytes),
+			Height: req.Height,
+			Time:   req.Time,
+			Signed: signedTx,
+			Store:  c.chainStore.Branch(),
+		}
+		for _, check := range c.proposalChecks {
+			if err := check(ctx, tx); err != nil {
+				return fmt.Errorf("tx %d: %w", i, err)
+			}
+		}
+	}
+	return nil
+}
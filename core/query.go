@@ -0,0 +1,80 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	abcitypes "github.com/cometbft/cometbft/abci/types"
+	cmtcrypto "github.com/cometbft/cometbft/api/cometbft/crypto/v1"
+	"github.com/sonata-labs/sonata/types/module"
+)
+
+var _ module.QueryRouter = (*Core)(nil)
+
+// RegisterQueryHandler serves queries under route with handler. Registering
+// the same route twice panics.
+func (c *Core) RegisterQueryHandler(route string, handler module.QueryHandler) {
+	route = "/" + strings.Trim(route, "/")
+	if _, exists := c.queryHandlers[route]; exists {
+		panic(fmt.Sprintf("query handler already registered for %s", route))
+	}
+	c.queryHandlers[route] = handler
+}
+
+// routeQuery returns the handler for the longest registered route that is a
+// prefix of path, and the remaining path segments.
+func (c *Core) routeQuery(path string) (module.QueryHandler, []string, bool) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for n := len(segments); n > 0; n-- {
+		if handler, ok := c.queryHandlers["/"+strings.Join(segments[:n], "/")]; ok {
+			return handler, segments[n:], true
+		}
+	}
+	return nil, nil, false
+}
+
+// query serves a routed query from the state committed at the requested
+// height, or the latest state for height 0.
+func (c *Core) query(ctx context.Context, req *abcitypes.QueryRequest, handler module.QueryHandler, args []string) (*abcitypes.QueryResponse, error) {
+	height, _, err := c.chainStore.GetLastBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	store := c.chainStore
+	if req.Height != 0 && req.Height != height {
+		view, err := c.chainStore.AtHeight(req.Height)
+		if err != nil {
+			return queryError(err), nil
+		}
+		defer view.Close()
+		store, height = view, req.Height
+	}
+
+	key, err := handler(ctx, store, args)
+	if err != nil {
+		return queryError(err), nil
+	}
+	if len(key) == 0 {
+		return queryError(errors.New("query resolved to no state")), nil
+	}
+
+	value, op, err := store.GetWithProof(key)
+	if err != nil {
+		return nil, err
+	}
+	resp := &abcitypes.QueryResponse{Height: height, Key: key, Value: value}
+	if req.Prove {
+		resp.ProofOps = &cmtcrypto.ProofOps{Ops: []cmtcrypto.ProofOp{*op}}
+	}
+	if resp.Value == nil {
+		resp.Log = "not found"
+	}
+	return resp, nil
+}
+
+func queryError(err error) *abcitypes.QueryResponse {
+	return &abcitypes.QueryResponse{Code: 1, Log: err.Error()}
+}
@@ -0,0 +1,106 @@
+package core
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	abcitypes "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/libs/protoio"
+)
+
+// maxRecordSize bounds a single recorded message, large enough for a full
+// block of transactions.
+const maxRecordSize = 256 << 20
+
+// Recorder appends the InitChain, FinalizeBlock and Commit requests core
+// executes, each followed by core's response, to a log. The log lets
+// sonata replay re-execute what a node executed.
+type Recorder struct {
+	mu   sync.Mutex
+	file *os.File
+	buf  *bufio.Writer
+	w    protoio.Writer
+}
+
+// NewRecorder opens the log at path, appending to it if it exists.
+func NewRecorder(path string) (*Recorder, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("opening recording: %w", err)
+	}
+	buf := bufio.NewWriter(file)
+	return &Recorder{file: file, buf: buf, w: protoio.NewDelimitedWriter(buf)}, nil
+}
+
+// Record appends a request and its response to the log.
+func (r *Recorder) Record(req *abcitypes.Request, resp *abcitypes.Response) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, err := r.w.WriteMsg(req); err != nil {
+		return err
+	}
+	if _, err := r.w.WriteMsg(resp); err != nil {
+		return err
+	}
+	return r.buf.Flush()
+}
+
+// Close flushes and closes the log.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.buf.Flush(); err != nil {
+		r.file.Close()
+		return err
+	}
+	return r.file.Close()
+}
+
+// ReadRecording calls fn with every request and response in the log at path,
+// in the order they were recorded. A record cut short by a crash at the end
+// of the log is ignored.
+func ReadRecording(path string, fn func(req *abcitypes.Request, resp *abcitypes.Response) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("opening recording: %w", err)
+	}
+	defer file.Close()
+
+	r := protoio.NewDelimitedReader(bufio.NewReader(file), maxRecordSize)
+	for {
+		req := &abcitypes.Request{}
+		if _, err := r.ReadMsg(req); errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("reading recording: %w", err)
+		}
+
+		resp := &abcitypes.Response{}
+		if _, err := r.ReadMsg(resp); errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("reading recording: %w", err)
+		}
+
+		if err := fn(req, resp); err != nil {
+			return err
+		}
+	}
+}
+
+// record appends an executed request to the recording, if there is one.
+// Failing to record is logged and does not stop the chain.
+func (c *Core) record(req *abcitypes.Request, resp *abcitypes.Response) {
+	if c.recorder == nil {
+		return
+	}
+	if err := c.recorder.Record(req, resp); err != nil {
+		c.logger.Errorw("failed to record abci request", "error", err)
+	}
+}
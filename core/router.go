@@ -0,0 +1,96 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	chainv1 "github.com/sonata-labs/sonata/gen/chain/v1"
+	"github.com/sonata-labs/sonata/store/chainstore"
+	"github.com/sonata-labs/sonata/types/module"
+	"google.golang.org/protobuf/proto"
+)
+
+var _ module.MsgRouter = (*Core)(nil)
+
+// GasPerTxByte is charged for every byte of a transaction, so large bodies
+// such as DDEX messages cost more to include in a block.
+const GasPerTxByte uint64 = 10
+
+// msgRoute is the handler registered for a transaction body and the gas
+// charged before it runs.
+type msgRoute struct {
+	handler module.MsgHandler
+	baseGas uint64
+}
+
+// RegisterMsgHandler routes transactions whose body is of the given oneof
+// wrapper type to handler. Registering the same body twice panics.
+func (c *Core) RegisterMsgHandler(body any, baseGas uint64, handler module.MsgHandler) {
+	bodyType := reflect.TypeOf(body)
+	if _, exists := c.msgHandlers[bodyType]; exists {
+		panic(fmt.Sprintf("msg handler already registered for %s", bodyType))
+	}
+	c.msgHandlers[bodyType] = msgRoute{handler: handler, baseGas: baseGas}
+}
+
+// RegisterAnteHandler adds a check run on every transaction before its body
+// handler, in CheckTx and again in FinalizeBlock.
+func (c *Core) RegisterAnteHandler(handler module.AnteHandler) {
+	c.anteHandlers = append(c.anteHandlers, handler)
+}
+
+// RegisterProposalCheck adds a check run on every transaction of a block
+// proposal in ProcessProposal.
+func (c *Core) RegisterProposalCheck(check module.AnteHandler) {
+	c.proposalChecks = append(c.proposalChecks, check)
+}
+
+// runAnteHandlers runs the registered ante handlers in order, stopping at the
+// first rejection.
+func (c *Core) runAnteHandlers(ctx context.Context, tx *module.Tx) error {
+	for _, ante := range c.anteHandlers {
+		if err := ante(ctx, tx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeTx unmarshals a signed transaction and looks up the route for its
+// body. Transactions without a registered handler are rejected.
+func (c *Core) decodeTx(txBytes []byte) (*chainv1.SignedTransaction, msgRoute, error) {
+	signedTx := &chainv1.SignedTransaction{}
+	if err := proto.Unmarshal(txBytes, signedTx); err != nil {
+		return nil, msgRoute{}, fmt.Errorf("tx not a signed transaction: %w", err)
+	}
+
+	tx := signedTx.Transaction
+	if tx == nil || tx.Header == nil {
+		return nil, msgRoute{}, errors.New("tx missing header")
+	}
+	if tx.Body == nil || tx.Body.Body == nil {
+		return nil, msgRoute{}, errors.New("tx missing body")
+	}
+
+	route, ok := c.msgHandlers[reflect.TypeOf(tx.Body.Body)]
+	if !ok {
+		return nil, msgRoute{}, fmt.Errorf("unknown transaction body %T", tx.Body.Body)
+	}
+	return signedTx, route, nil
+}
+
+// intrinsicGas returns a meter for a transaction charged with its size and
+// its body's base gas. The returned error is set if that alone exceeds the
+// transaction's gas limit.
+func intrinsicGas(signedTx *chainv1.SignedTransaction, route msgRoute, size int) (*chainstore.GasMeter, error) {
+	meter := chainstore.NewGasMeter(signedTx.Transaction.Header.GasLimit)
+	if err := meter.Consume(GasPerTxByte*uint64(size), "tx size"); err != nil {
+		return meter, err
+	}
+	if err := meter.Consume(route.baseGas, "base gas"); err != nil {
+		return meter, err
+	}
+	return meter, nil
+}
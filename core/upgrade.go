@@ -0,0 +1,66 @@
+package core
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sonata-labs/sonata/types/module"
+)
+
+var _ module.UpgradeRouter = (*Core)(nil)
+
+// RegisterUpgradeHandler makes this binary apply the named upgrade plan.
+// Registering the same name twice panics.
+func (c *Core) RegisterUpgradeHandler(name string, handler module.UpgradeHandler) {
+	if _, exists := c.upgradeHandlers[name]; exists {
+		panic(fmt.Sprintf("upgrade handler already registered for %s", name))
+	}
+	c.upgradeHandlers[name] = handler
+}
+
+// applyUpgrade runs the scheduled upgrade when the block at height reaches
+// it. A binary without a handler for the plan returns an error, which halts
+// the node at that height until it is restarted with a binary that has one.
+// A binary with the handler also refuses to run before the plan's height,
+// since it may already execute blocks differently from the old binary.
+func (c *Core) applyUpgrade(ctx context.Context, height int64) error {
+	plan, err := c.batch.GetUpgradePlan()
+	if err != nil {
+		return fmt.Errorf("reading upgrade plan: %w", err)
+	}
+	if plan == nil {
+		return nil
+	}
+
+	handler, ok := c.upgradeHandlers[plan.Name]
+	if height < plan.Height {
+		if ok {
+			return fmt.Errorf("binary has the %q upgrade, which must not run before height %d", plan.Name, plan.Height)
+		}
+		return nil
+	}
+	if !ok {
+		c.logger.Errorw("UPGRADE NEEDED: halting, restart with a binary that has the upgrade",
+			"upgrade", plan.Name, "height", plan.Height, "info", plan.Info)
+		return fmt.Errorf("upgrade %q needed at height %d", plan.Name, plan.Height)
+	}
+
+	c.logger.Infow("applying upgrade", "upgrade", plan.Name, "height", height)
+	branch := c.batch.Branch()
+	if err := handler(ctx, branch, plan); err != nil {
+		return fmt.Errorf("applying upgrade %q: %w", plan.Name, err)
+	}
+	if err := branch.Write(); err != nil {
+		return err
+	}
+	// the chain store's key layout changes with the upgrade it belongs to.
+	// Migrations iterate the state, which a branch cannot, so they run on
+	// the block's batch; an error halts the node before it is committed.
+	if err := c.batch.Migrate(ctx, plan.Name); err != nil {
+		return fmt.Errorf("applying upgrade %q: %w", plan.Name, err)
+	}
+	if err := c.batch.StoreUpgradeDone(plan.Name, height); err != nil {
+		return err
+	}
+	return c.batch.DeleteUpgradePlan()
+}
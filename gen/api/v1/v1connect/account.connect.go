@@ -35,11 +35,19 @@ const (
 const (
 	// AccountGetAccountProcedure is the fully-qualified name of the Account's GetAccount RPC.
 	AccountGetAccountProcedure = "/api.v1.Account/GetAccount"
+	// AccountListAccountsProcedure is the fully-qualified name of the Account's ListAccounts RPC.
+	AccountListAccountsProcedure = "/api.v1.Account/ListAccounts"
+	// AccountCreateAccountProcedure is the fully-qualified name of the Account's CreateAccount RPC.
+	AccountCreateAccountProcedure = "/api.v1.Account/CreateAccount"
 )
 
 // AccountClient is a client for the api.v1.Account service.
 type AccountClient interface {
 	GetAccount(context.Context, *connect.Request[v1.GetAccountRequest]) (*connect.Response[v1.GetAccountResponse], error)
+	// lists the accounts in address order, a page at a time
+	ListAccounts(context.Context, *connect.Request[v1.ListAccountsRequest]) (*connect.Response[v1.ListAccountsResponse], error)
+	// utility rpc to create transaction bytes for a wallet to sign and send
+	CreateAccount(context.Context, *connect.Request[v1.CreateAccountRequest]) (*connect.Response[v1.CreateAccountResponse], error)
 }
 
 // NewAccountClient constructs a client for the api.v1.Account service. By default, it uses the
@@ -59,12 +67,26 @@ func NewAccountClient(httpClient connect.HTTPClient, baseURL string, opts ...con
 			connect.WithSchema(accountMethods.ByName("GetAccount")),
 			connect.WithClientOptions(opts...),
 		),
+		listAccounts: connect.NewClient[v1.ListAccountsRequest, v1.ListAccountsResponse](
+			httpClient,
+			baseURL+AccountListAccountsProcedure,
+			connect.WithSchema(accountMethods.ByName("ListAccounts")),
+			connect.WithClientOptions(opts...),
+		),
+		createAccount: connect.NewClient[v1.CreateAccountRequest, v1.CreateAccountResponse](
+			httpClient,
+			baseURL+AccountCreateAccountProcedure,
+			connect.WithSchema(accountMethods.ByName("CreateAccount")),
+			connect.WithClientOptions(opts...),
+		),
 	}
 }
 
 // accountClient implements AccountClient.
 type accountClient struct {
-	getAccount *connect.Client[v1.GetAccountRequest, v1.GetAccountResponse]
+	getAccount    *connect.Client[v1.GetAccountRequest, v1.GetAccountResponse]
+	listAccounts  *connect.Client[v1.ListAccountsRequest, v1.ListAccountsResponse]
+	createAccount *connect.Client[v1.CreateAccountRequest, v1.CreateAccountResponse]
 }
 
 // GetAccount calls api.v1.Account.GetAccount.
@@ -72,9 +94,23 @@ func (c *accountClient) GetAccount(ctx context.Context, req *connect.Request[v1.
 	return c.getAccount.CallUnary(ctx, req)
 }
 
+// ListAccounts calls api.v1.Account.ListAccounts.
+func (c *accountClient) ListAccounts(ctx context.Context, req *connect.Request[v1.ListAccountsRequest]) (*connect.Response[v1.ListAccountsResponse], error) {
+	return c.listAccounts.CallUnary(ctx, req)
+}
+
+// CreateAccount calls api.v1.Account.CreateAccount.
+func (c *accountClient) CreateAccount(ctx context.Context, req *connect.Request[v1.CreateAccountRequest]) (*connect.Response[v1.CreateAccountResponse], error) {
+	return c.createAccount.CallUnary(ctx, req)
+}
+
 // AccountHandler is an implementation of the api.v1.Account service.
 type AccountHandler interface {
 	GetAccount(context.Context, *connect.Request[v1.GetAccountRequest]) (*connect.Response[v1.GetAccountResponse], error)
+	// lists the accounts in address order, a page at a time
+	ListAccounts(context.Context, *connect.Request[v1.ListAccountsRequest]) (*connect.Response[v1.ListAccountsResponse], error)
+	// utility rpc to create transaction bytes for a wallet to sign and send
+	CreateAccount(context.Context, *connect.Request[v1.CreateAccountRequest]) (*connect.Response[v1.CreateAccountResponse], error)
 }
 
 // NewAccountHandler builds an HTTP handler from the service implementation. It returns the path on
@@ -90,10 +126,26 @@ func NewAccountHandler(svc AccountHandler, opts ...connect.HandlerOption) (strin
 		connect.WithSchema(accountMethods.ByName("GetAccount")),
 		connect.WithHandlerOptions(opts...),
 	)
+	accountListAccountsHandler := connect.NewUnaryHandler(
+		AccountListAccountsProcedure,
+		svc.ListAccounts,
+		connect.WithSchema(accountMethods.ByName("ListAccounts")),
+		connect.WithHandlerOptions(opts...),
+	)
+	accountCreateAccountHandler := connect.NewUnaryHandler(
+		AccountCreateAccountProcedure,
+		svc.CreateAccount,
+		connect.WithSchema(accountMethods.ByName("CreateAccount")),
+		connect.WithHandlerOptions(opts...),
+	)
 	return "/api.v1.Account/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case AccountGetAccountProcedure:
 			accountGetAccountHandler.ServeHTTP(w, r)
+		case AccountListAccountsProcedure:
+			accountListAccountsHandler.ServeHTTP(w, r)
+		case AccountCreateAccountProcedure:
+			accountCreateAccountHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -106,3 +158,11 @@ type UnimplementedAccountHandler struct{}
 func (UnimplementedAccountHandler) GetAccount(context.Context, *connect.Request[v1.GetAccountRequest]) (*connect.Response[v1.GetAccountResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.Account.GetAccount is not implemented"))
 }
+
+func (UnimplementedAccountHandler) ListAccounts(context.Context, *connect.Request[v1.ListAccountsRequest]) (*connect.Response[v1.ListAccountsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.Account.ListAccounts is not implemented"))
+}
+
+func (UnimplementedAccountHandler) CreateAccount(context.Context, *connect.Request[v1.CreateAccountRequest]) (*connect.Response[v1.CreateAccountResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.Account.CreateAccount is not implemented"))
+}
@@ -42,6 +42,11 @@ const (
 	// StorageDownloadFileChunkProcedure is the fully-qualified name of the Storage's DownloadFileChunk
 	// RPC.
 	StorageDownloadFileChunkProcedure = "/api.v1.Storage/DownloadFileChunk"
+	// StorageListUploadsProcedure is the fully-qualified name of the Storage's ListUploads RPC.
+	StorageListUploadsProcedure = "/api.v1.Storage/ListUploads"
+	// StorageGetBootstrapProgressProcedure is the fully-qualified name of the Storage's
+	// GetBootstrapProgress RPC.
+	StorageGetBootstrapProgressProcedure = "/api.v1.Storage/GetBootstrapProgress"
 )
 
 // StorageClient is a client for the api.v1.Storage service.
@@ -50,6 +55,10 @@ type StorageClient interface {
 	UploadChunk(context.Context, *connect.Request[v1.UploadChunkRequest]) (*connect.Response[v1.UploadChunkResponse], error)
 	DownloadFile(context.Context, *connect.Request[v1.DownloadFileRequest]) (*connect.Response[v1.DownloadFileResponse], error)
 	DownloadFileChunk(context.Context, *connect.Request[v1.DownloadFileChunkRequest]) (*connect.ServerStreamForClient[v1.DownloadFileChunkResponse], error)
+	// lists the uploads recorded on chain in transcoded CID order, a page at a time
+	ListUploads(context.Context, *connect.Request[v1.ListUploadsRequest]) (*connect.Response[v1.ListUploadsResponse], error)
+	// reports how far the node is in fetching the files it serves from peers
+	GetBootstrapProgress(context.Context, *connect.Request[v1.GetBootstrapProgressRequest]) (*connect.Response[v1.GetBootstrapProgressResponse], error)
 }
 
 // NewStorageClient constructs a client for the api.v1.Storage service. By default, it uses the
@@ -87,15 +96,29 @@ func NewStorageClient(httpClient connect.HTTPClient, baseURL string, opts ...con
 			connect.WithSchema(storageMethods.ByName("DownloadFileChunk")),
 			connect.WithClientOptions(opts...),
 		),
+		listUploads: connect.NewClient[v1.ListUploadsRequest, v1.ListUploadsResponse](
+			httpClient,
+			baseURL+StorageListUploadsProcedure,
+			connect.WithSchema(storageMethods.ByName("ListUploads")),
+			connect.WithClientOptions(opts...),
+		),
+		getBootstrapProgress: connect.NewClient[v1.GetBootstrapProgressRequest, v1.GetBootstrapProgressResponse](
+			httpClient,
+			baseURL+StorageGetBootstrapProgressProcedure,
+			connect.WithSchema(storageMethods.ByName("GetBootstrapProgress")),
+			connect.WithClientOptions(opts...),
+		),
 	}
 }
 
 // storageClient implements StorageClient.
 type storageClient struct {
-	upload            *connect.Client[v1.UploadRequest, v1.UploadResponse]
-	uploadChunk       *connect.Client[v1.UploadChunkRequest, v1.UploadChunkResponse]
-	downloadFile      *connect.Client[v1.DownloadFileRequest, v1.DownloadFileResponse]
-	downloadFileChunk *connect.Client[v1.DownloadFileChunkRequest, v1.DownloadFileChunkResponse]
+	upload               *connect.Client[v1.UploadRequest, v1.UploadResponse]
+	uploadChunk          *connect.Client[v1.UploadChunkRequest, v1.UploadChunkResponse]
+	downloadFile         *connect.Client[v1.DownloadFileRequest, v1.DownloadFileResponse]
+	downloadFileChunk    *connect.Client[v1.DownloadFileChunkRequest, v1.DownloadFileChunkResponse]
+	listUploads          *connect.Client[v1.ListUploadsRequest, v1.ListUploadsResponse]
+	getBootstrapProgress *connect.Client[v1.GetBootstrapProgressRequest, v1.GetBootstrapProgressResponse]
 }
 
 // Upload calls api.v1.Storage.Upload.
@@ -118,12 +141,26 @@ func (c *storageClient) DownloadFileChunk(ctx context.Context, req *connect.Requ
 	return c.downloadFileChunk.CallServerStream(ctx, req)
 }
 
+// ListUploads calls api.v1.Storage.ListUploads.
+func (c *storageClient) ListUploads(ctx context.Context, req *connect.Request[v1.ListUploadsRequest]) (*connect.Response[v1.ListUploadsResponse], error) {
+	return c.listUploads.CallUnary(ctx, req)
+}
+
+// GetBootstrapProgress calls api.v1.Storage.GetBootstrapProgress.
+func (c *storageClient) GetBootstrapProgress(ctx context.Context, req *connect.Request[v1.GetBootstrapProgressRequest]) (*connect.Response[v1.GetBootstrapProgressResponse], error) {
+	return c.getBootstrapProgress.CallUnary(ctx, req)
+}
+
 // StorageHandler is an implementation of the api.v1.Storage service.
 type StorageHandler interface {
 	Upload(context.Context, *connect.Request[v1.UploadRequest]) (*connect.Response[v1.UploadResponse], error)
 	UploadChunk(context.Context, *connect.Request[v1.UploadChunkRequest]) (*connect.Response[v1.UploadChunkResponse], error)
 	DownloadFile(context.Context, *connect.Request[v1.DownloadFileRequest]) (*connect.Response[v1.DownloadFileResponse], error)
 	DownloadFileChunk(context.Context, *connect.Request[v1.DownloadFileChunkRequest], *connect.ServerStream[v1.DownloadFileChunkResponse]) error
+	// lists the uploads recorded on chain in transcoded CID order, a page at a time
+	ListUploads(context.Context, *connect.Request[v1.ListUploadsRequest]) (*connect.Response[v1.ListUploadsResponse], error)
+	// reports how far the node is in fetching the files it serves from peers
+	GetBootstrapProgress(context.Context, *connect.Request[v1.GetBootstrapProgressRequest]) (*connect.Response[v1.GetBootstrapProgressResponse], error)
 }
 
 // NewStorageHandler builds an HTTP handler from the service implementation. It returns the path on
@@ -157,6 +194,18 @@ func NewStorageHandler(svc StorageHandler, opts ...connect.HandlerOption) (strin
 		connect.WithSchema(storageMethods.ByName("DownloadFileChunk")),
 		connect.WithHandlerOptions(opts...),
 	)
+	storageListUploadsHandler := connect.NewUnaryHandler(
+		StorageListUploadsProcedure,
+		svc.ListUploads,
+		connect.WithSchema(storageMethods.ByName("ListUploads")),
+		connect.WithHandlerOptions(opts...),
+	)
+	storageGetBootstrapProgressHandler := connect.NewUnaryHandler(
+		StorageGetBootstrapProgressProcedure,
+		svc.GetBootstrapProgress,
+		connect.WithSchema(storageMethods.ByName("GetBootstrapProgress")),
+		connect.WithHandlerOptions(opts...),
+	)
 	return "/api.v1.Storage/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case StorageUploadProcedure:
@@ -167,6 +216,10 @@ func NewStorageHandler(svc StorageHandler, opts ...connect.HandlerOption) (strin
 			storageDownloadFileHandler.ServeHTTP(w, r)
 		case StorageDownloadFileChunkProcedure:
 			storageDownloadFileChunkHandler.ServeHTTP(w, r)
+		case StorageListUploadsProcedure:
+			storageListUploadsHandler.ServeHTTP(w, r)
+		case StorageGetBootstrapProgressProcedure:
+			storageGetBootstrapProgressHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -191,3 +244,11 @@ func (UnimplementedStorageHandler) DownloadFile(context.Context, *connect.Reques
 func (UnimplementedStorageHandler) DownloadFileChunk(context.Context, *connect.Request[v1.DownloadFileChunkRequest], *connect.ServerStream[v1.DownloadFileChunkResponse]) error {
 	return connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.Storage.DownloadFileChunk is not implemented"))
 }
+
+func (UnimplementedStorageHandler) ListUploads(context.Context, *connect.Request[v1.ListUploadsRequest]) (*connect.Response[v1.ListUploadsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.Storage.ListUploads is not implemented"))
+}
+
+func (UnimplementedStorageHandler) GetBootstrapProgress(context.Context, *connect.Request[v1.GetBootstrapProgressRequest]) (*connect.Response[v1.GetBootstrapProgressResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("api.v1.Storage.GetBootstrapProgress is not implemented"))
+}
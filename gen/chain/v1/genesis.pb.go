@@ -0,0 +1,497 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.33.0
+// 	protoc        (unknown)
+// source: chain/v1/genesis.proto
+
+package v1
+
+import (
+	v1 "github.com/sonata-labs/sonata/gen/account/v1"
+	v12 "github.com/sonata-labs/sonata/gen/ddex/v1"
+	v11 "github.com/sonata-labs/sonata/gen/storage/v1"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	anypb "google.golang.org/protobuf/types/known/anypb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Chain parameters every validator must agree on.
+type Params struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// gas all transactions in a block may use together, 0 for no limit
+	MaxBlockGas uint64 `protobuf:"varint,1,opt,name=max_block_gas,json=maxBlockGas,proto3" json:"max_block_gas,omitempty"`
+	// lowest gas price a transaction may pay
+	MinGasPrice uint64 `protobuf:"varint,2,opt,name=min_gas_price,json=minGasPrice,proto3" json:"min_gas_price,omitempty"`
+	// account allowed to schedule software upgrades, none if empty
+	UpgradeAuthority string `protobuf:"bytes,3,opt,name=upgrade_authority,json=upgradeAuthority,proto3" json:"upgrade_authority,omitempty"`
+	// largest transaction a block may carry, in bytes, 0 for no limit
+	MaxTxBytes uint64 `protobuf:"varint,4,opt,name=max_tx_bytes,json=maxTxBytes,proto3" json:"max_tx_bytes,omitempty"`
+}
+
+func (x *Params) Reset() {
+	*x = Params{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_chain_v1_genesis_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Params) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Params) ProtoMessage() {}
+
+func (x *Params) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_v1_genesis_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Params.ProtoReflect.Descriptor instead.
+func (*Params) Descriptor() ([]byte, []int) {
+	return file_chain_v1_genesis_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Params) GetMaxBlockGas() uint64 {
+	if x != nil {
+		return x.MaxBlockGas
+	}
+	return 0
+}
+
+func (x *Params) GetMinGasPrice() uint64 {
+	if x != nil {
+		return x.MinGasPrice
+	}
+	return 0
+}
+
+func (x *Params) GetUpgradeAuthority() string {
+	if x != nil {
+		return x.UpgradeAuthority
+	}
+	return ""
+}
+
+func (x *Params) GetMaxTxBytes() uint64 {
+	if x != nil {
+		return x.MaxTxBytes
+	}
+	return 0
+}
+
+// The app_state of a genesis file, imported by each module in InitChain and
+// produced by `sonata export`.
+type GenesisState struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Params   *Params                  `protobuf:"bytes,1,opt,name=params,proto3" json:"params,omitempty"`
+	Accounts []*v1.Account            `protobuf:"bytes,2,rep,name=accounts,proto3" json:"accounts,omitempty"`
+	Uploads  []*v11.FileUploadMessage `protobuf:"bytes,3,rep,name=uploads,proto3" json:"uploads,omitempty"`
+	// the DDEX messages recorded on chain, by the account that submitted them
+	Ddex []*GenesisDDEXMessages `protobuf:"bytes,4,rep,name=ddex,proto3" json:"ddex,omitempty"`
+	// the scheduled software upgrade, if any
+	UpgradePlan *UpgradePlan `protobuf:"bytes,5,opt,name=upgrade_plan,json=upgradePlan,proto3" json:"upgrade_plan,omitempty"`
+	// the upgrades applied so far, which cannot be scheduled again
+	UpgradesDone []*UpgradeDone `protobuf:"bytes,6,rep,name=upgrades_done,json=upgradesDone,proto3" json:"upgrades_done,omitempty"`
+	// the app state of modules added to the app, by module name
+	Modules map[string]*anypb.Any `protobuf:"bytes,7,rep,name=modules,proto3" json:"modules,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *GenesisState) Reset() {
+	*x = GenesisState{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_chain_v1_genesis_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GenesisState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenesisState) ProtoMessage() {}
+
+func (x *GenesisState) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_v1_genesis_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenesisState.ProtoReflect.Descriptor instead.
+func (*GenesisState) Descriptor() ([]byte, []int) {
+	return file_chain_v1_genesis_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GenesisState) GetParams() *Params {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+func (x *GenesisState) GetAccounts() []*v1.Account {
+	if x != nil {
+		return x.Accounts
+	}
+	return nil
+}
+
+func (x *GenesisState) GetUploads() []*v11.FileUploadMessage {
+	if x != nil {
+		return x.Uploads
+	}
+	return nil
+}
+
+func (x *GenesisState) GetDdex() []*GenesisDDEXMessages {
+	if x != nil {
+		return x.Ddex
+	}
+	return nil
+}
+
+func (x *GenesisState) GetUpgradePlan() *UpgradePlan {
+	if x != nil {
+		return x.UpgradePlan
+	}
+	return nil
+}
+
+func (x *GenesisState) GetUpgradesDone() []*UpgradeDone {
+	if x != nil {
+		return x.UpgradesDone
+	}
+	return nil
+}
+
+func (x *GenesisState) GetModules() map[string]*anypb.Any {
+	if x != nil {
+		return x.Modules
+	}
+	return nil
+}
+
+// The DDEX messages submitted by one account. Message ids are unique per
+// kind among the messages of an account.
+type GenesisDDEXMessages struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Address       string                     `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Releases      []*v12.NewReleaseMessage   `protobuf:"bytes,2,rep,name=releases,proto3" json:"releases,omitempty"`
+	CatalogLists  []*v12.CatalogListMessage  `protobuf:"bytes,3,rep,name=catalog_lists,json=catalogLists,proto3" json:"catalog_lists,omitempty"`
+	PurgeReleases []*v12.PurgeReleaseMessage `protobuf:"bytes,4,rep,name=purge_releases,json=purgeReleases,proto3" json:"purge_releases,omitempty"`
+	Pies          []*v12.PieMessage          `protobuf:"bytes,5,rep,name=pies,proto3" json:"pies,omitempty"`
+	PieRequests   []*v12.PieRequestMessage   `protobuf:"bytes,6,rep,name=pie_requests,json=pieRequests,proto3" json:"pie_requests,omitempty"`
+	Meads         []*v12.MeadMessage         `protobuf:"bytes,7,rep,name=meads,proto3" json:"meads,omitempty"`
+}
+
+func (x *GenesisDDEXMessages) Reset() {
+	*x = GenesisDDEXMessages{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_chain_v1_genesis_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GenesisDDEXMessages) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenesisDDEXMessages) ProtoMessage() {}
+
+func (x *GenesisDDEXMessages) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_v1_genesis_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenesisDDEXMessages.ProtoReflect.Descriptor instead.
+func (*GenesisDDEXMessages) Descriptor() ([]byte, []int) {
+	return file_chain_v1_genesis_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GenesisDDEXMessages) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *GenesisDDEXMessages) GetReleases() []*v12.NewReleaseMessage {
+	if x != nil {
+		return x.Releases
+	}
+	return nil
+}
+
+func (x *GenesisDDEXMessages) GetCatalogLists() []*v12.CatalogListMessage {
+	if x != nil {
+		return x.CatalogLists
+	}
+	return nil
+}
+
+func (x *GenesisDDEXMessages) GetPurgeReleases() []*v12.PurgeReleaseMessage {
+	if x != nil {
+		return x.PurgeReleases
+	}
+	return nil
+}
+
+func (x *GenesisDDEXMessages) GetPies() []*v12.PieMessage {
+	if x != nil {
+		return x.Pies
+	}
+	return nil
+}
+
+func (x *GenesisDDEXMessages) GetPieRequests() []*v12.PieRequestMessage {
+	if x != nil {
+		return x.PieRequests
+	}
+	return nil
+}
+
+func (x *GenesisDDEXMessages) GetMeads() []*v12.MeadMessage {
+	if x != nil {
+		return x.Meads
+	}
+	return nil
+}
+
+var File_chain_v1_genesis_proto protoreflect.FileDescriptor
+
+var file_chain_v1_genesis_proto_rawDesc = []byte{
+	0x0a, 0x16, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x2f, 0x76, 0x31, 0x2f, 0x67, 0x65, 0x6e, 0x65, 0x73,
+	0x69, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x08, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x2e,
+	0x76, 0x31, 0x1a, 0x13, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x2f, 0x76, 0x31, 0x2f, 0x76,
+	0x31, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x10, 0x64, 0x64, 0x65, 0x78, 0x2f, 0x76, 0x31,
+	0x2f, 0x76, 0x31, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x16, 0x63, 0x68, 0x61, 0x69, 0x6e,
+	0x2f, 0x76, 0x31, 0x2f, 0x75, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x1a, 0x19, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2f, 0x61, 0x6e, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x13, 0x73, 0x74,
+	0x6f, 0x72, 0x61, 0x67, 0x65, 0x2f, 0x76, 0x31, 0x2f, 0x76, 0x31, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x22, 0x9f, 0x01, 0x0a, 0x06, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x12, 0x22, 0x0a, 0x0d,
+	0x6d, 0x61, 0x78, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x67, 0x61, 0x73, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x0b, 0x6d, 0x61, 0x78, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x47, 0x61, 0x73,
+	0x12, 0x22, 0x0a, 0x0d, 0x6d, 0x69, 0x6e, 0x5f, 0x67, 0x61, 0x73, 0x5f, 0x70, 0x72, 0x69, 0x63,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x6d, 0x69, 0x6e, 0x47, 0x61, 0x73, 0x50,
+	0x72, 0x69, 0x63, 0x65, 0x12, 0x2b, 0x0a, 0x11, 0x75, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x5f,
+	0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x10, 0x75, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x74,
+	0x79, 0x12, 0x20, 0x0a, 0x0c, 0x6d, 0x61, 0x78, 0x5f, 0x74, 0x78, 0x5f, 0x62, 0x79, 0x74, 0x65,
+	0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x6d, 0x61, 0x78, 0x54, 0x78, 0x42, 0x79,
+	0x74, 0x65, 0x73, 0x22, 0xdc, 0x03, 0x0a, 0x0c, 0x47, 0x65, 0x6e, 0x65, 0x73, 0x69, 0x73, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x12, 0x28, 0x0a, 0x06, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x2e, 0x76, 0x31, 0x2e,
+	0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x52, 0x06, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x12, 0x2f,
+	0x0a, 0x08, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x13, 0x2e, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x63,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x08, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x12,
+	0x37, 0x0a, 0x07, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x1d, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69,
+	0x6c, 0x65, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52,
+	0x07, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x73, 0x12, 0x31, 0x0a, 0x04, 0x64, 0x64, 0x65, 0x78,
+	0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x2e, 0x76,
+	0x31, 0x2e, 0x47, 0x65, 0x6e, 0x65, 0x73, 0x69, 0x73, 0x44, 0x44, 0x45, 0x58, 0x4d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x73, 0x52, 0x04, 0x64, 0x64, 0x65, 0x78, 0x12, 0x38, 0x0a, 0x0c, 0x75,
+	0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x5f, 0x70, 0x6c, 0x61, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x15, 0x2e, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x70, 0x67,
+	0x72, 0x61, 0x64, 0x65, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x0b, 0x75, 0x70, 0x67, 0x72, 0x61, 0x64,
+	0x65, 0x50, 0x6c, 0x61, 0x6e, 0x12, 0x3a, 0x0a, 0x0d, 0x75, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65,
+	0x73, 0x5f, 0x64, 0x6f, 0x6e, 0x65, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x63,
+	0x68, 0x61, 0x69, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x44,
+	0x6f, 0x6e, 0x65, 0x52, 0x0c, 0x75, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x73, 0x44, 0x6f, 0x6e,
+	0x65, 0x12, 0x3d, 0x0a, 0x07, 0x6d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x07, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x23, 0x2e, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65,
+	0x6e, 0x65, 0x73, 0x69, 0x73, 0x53, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x4d, 0x6f, 0x64, 0x75, 0x6c,
+	0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x6d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x73,
+	0x1a, 0x50, 0x0a, 0x0c, 0x4d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b,
+	0x65, 0x79, 0x12, 0x2a, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x14, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x41, 0x6e, 0x79, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02,
+	0x38, 0x01, 0x22, 0x82, 0x03, 0x0a, 0x13, 0x47, 0x65, 0x6e, 0x65, 0x73, 0x69, 0x73, 0x44, 0x44,
+	0x45, 0x58, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x64,
+	0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x64, 0x64,
+	0x72, 0x65, 0x73, 0x73, 0x12, 0x36, 0x0a, 0x08, 0x72, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x73,
+	0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x64, 0x64, 0x65, 0x78, 0x2e, 0x76, 0x31,
+	0x2e, 0x4e, 0x65, 0x77, 0x52, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x52, 0x08, 0x72, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x73, 0x12, 0x40, 0x0a, 0x0d,
+	0x63, 0x61, 0x74, 0x61, 0x6c, 0x6f, 0x67, 0x5f, 0x6c, 0x69, 0x73, 0x74, 0x73, 0x18, 0x03, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x64, 0x64, 0x65, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x61,
+	0x74, 0x61, 0x6c, 0x6f, 0x67, 0x4c, 0x69, 0x73, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x52, 0x0c, 0x63, 0x61, 0x74, 0x61, 0x6c, 0x6f, 0x67, 0x4c, 0x69, 0x73, 0x74, 0x73, 0x12, 0x43,
+	0x0a, 0x0e, 0x70, 0x75, 0x72, 0x67, 0x65, 0x5f, 0x72, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x73,
+	0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x64, 0x64, 0x65, 0x78, 0x2e, 0x76, 0x31,
+	0x2e, 0x50, 0x75, 0x72, 0x67, 0x65, 0x52, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x4d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x52, 0x0d, 0x70, 0x75, 0x72, 0x67, 0x65, 0x52, 0x65, 0x6c, 0x65, 0x61,
+	0x73, 0x65, 0x73, 0x12, 0x27, 0x0a, 0x04, 0x70, 0x69, 0x65, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x13, 0x2e, 0x64, 0x64, 0x65, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x69, 0x65, 0x4d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x04, 0x70, 0x69, 0x65, 0x73, 0x12, 0x3d, 0x0a, 0x0c,
+	0x70, 0x69, 0x65, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x18, 0x06, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x64, 0x64, 0x65, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x69, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x0b,
+	0x70, 0x69, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x12, 0x2a, 0x0a, 0x05, 0x6d,
+	0x65, 0x61, 0x64, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x64, 0x64, 0x65,
+	0x78, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x65, 0x61, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x52, 0x05, 0x6d, 0x65, 0x61, 0x64, 0x73, 0x42, 0x2c, 0x5a, 0x2a, 0x67, 0x69, 0x74, 0x68, 0x75,
+	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x73, 0x6f, 0x6e, 0x61, 0x74, 0x61, 0x2d, 0x6c, 0x61, 0x62,
+	0x73, 0x2f, 0x73, 0x6f, 0x6e, 0x61, 0x74, 0x61, 0x2f, 0x67, 0x65, 0x6e, 0x2f, 0x63, 0x68, 0x61,
+	0x69, 0x6e, 0x2f, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_chain_v1_genesis_proto_rawDescOnce sync.Once
+	file_chain_v1_genesis_proto_rawDescData = file_chain_v1_genesis_proto_rawDesc
+)
+
+func file_chain_v1_genesis_proto_rawDescGZIP() []byte {
+	file_chain_v1_genesis_proto_rawDescOnce.Do(func() {
+		file_chain_v1_genesis_proto_rawDescData = protoimpl.X.CompressGZIP(file_chain_v1_genesis_proto_rawDescData)
+	})
+	return file_chain_v1_genesis_proto_rawDescData
+}
+
+var file_chain_v1_genesis_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_chain_v1_genesis_proto_goTypes = []interface{}{
+	(*Params)(nil),                  // 0: chain.v1.Params
+	(*GenesisState)(nil),            // 1: chain.v1.GenesisState
+	(*GenesisDDEXMessages)(nil),     // 2: chain.v1.GenesisDDEXMessages
+	nil,                             // 3: chain.v1.GenesisState.ModulesEntry
+	(*v1.Account)(nil),              // 4: account.v1.Account
+	(*v11.FileUploadMessage)(nil),   // 5: storage.v1.FileUploadMessage
+	(*UpgradePlan)(nil),             // 6: chain.v1.UpgradePlan
+	(*UpgradeDone)(nil),             // 7: chain.v1.UpgradeDone
+	(*v12.NewReleaseMessage)(nil),   // 8: ddex.v1.NewReleaseMessage
+	(*v12.CatalogListMessage)(nil),  // 9: ddex.v1.CatalogListMessage
+	(*v12.PurgeReleaseMessage)(nil), // 10: ddex.v1.PurgeReleaseMessage
+	(*v12.PieMessage)(nil),          // 11: ddex.v1.PieMessage
+	(*v12.PieRequestMessage)(nil),   // 12: ddex.v1.PieRequestMessage
+	(*v12.MeadMessage)(nil),         // 13: ddex.v1.MeadMessage
+	(*anypb.Any)(nil),               // 14: google.protobuf.Any
+}
+var file_chain_v1_genesis_proto_depIdxs = []int32{
+	0,  // 0: chain.v1.GenesisState.params:type_name -> chain.v1.Params
+	4,  // 1: chain.v1.GenesisState.accounts:type_name -> account.v1.Account
+	5,  // 2: chain.v1.GenesisState.uploads:type_name -> storage.v1.FileUploadMessage
+	2,  // 3: chain.v1.GenesisState.ddex:type_name -> chain.v1.GenesisDDEXMessages
+	6,  // 4: chain.v1.GenesisState.upgrade_plan:type_name -> chain.v1.UpgradePlan
+	7,  // 5: chain.v1.GenesisState.upgrades_done:type_name -> chain.v1.UpgradeDone
+	3,  // 6: chain.v1.GenesisState.modules:type_name -> chain.v1.GenesisState.ModulesEntry
+	8,  // 7: chain.v1.GenesisDDEXMessages.releases:type_name -> ddex.v1.NewReleaseMessage
+	9,  // 8: chain.v1.GenesisDDEXMessages.catalog_lists:type_name -> ddex.v1.CatalogListMessage
+	10, // 9: chain.v1.GenesisDDEXMessages.purge_releases:type_name -> ddex.v1.PurgeReleaseMessage
+	11, // 10: chain.v1.GenesisDDEXMessages.pies:type_name -> ddex.v1.PieMessage
+	12, // 11: chain.v1.GenesisDDEXMessages.pie_requests:type_name -> ddex.v1.PieRequestMessage
+	13, // 12: chain.v1.GenesisDDEXMessages.meads:type_name -> ddex.v1.MeadMessage
+	14, // 13: chain.v1.GenesisState.ModulesEntry.value:type_name -> google.protobuf.Any
+	14, // [14:14] is the sub-list for method output_type
+	14, // [14:14] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
+}
+
+func init() { file_chain_v1_genesis_proto_init() }
+func file_chain_v1_genesis_proto_init() {
+	if File_chain_v1_genesis_proto != nil {
+		return
+	}
+	file_chain_v1_upgrade_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_chain_v1_genesis_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Params); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_chain_v1_genesis_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GenesisState); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_chain_v1_genesis_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GenesisDDEXMessages); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_chain_v1_genesis_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_chain_v1_genesis_proto_goTypes,
+		DependencyIndexes: file_chain_v1_genesis_proto_depIdxs,
+		MessageInfos:      file_chain_v1_genesis_proto_msgTypes,
+	}.Build()
+	File_chain_v1_genesis_proto = out.File
+	file_chain_v1_genesis_proto_rawDesc = nil
+	file_chain_v1_genesis_proto_goTypes = nil
+	file_chain_v1_genesis_proto_depIdxs = nil
+}
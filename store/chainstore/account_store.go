@@ -2,41 +2,39 @@ package chainstore
 
 import (
 	accountv1 "github.com/sonata-labs/sonata/gen/account/v1"
-	"google.golang.org/protobuf/proto"
 )
 
-func (c *ChainStore) StoreAccount(account *accountv1.Account) error {
-	if err := c.RequireBatch(); err != nil {
-		return err
-	}
-
-	writer := c.writer
+// Accounts holds the accounts by address.
+var Accounts = NewCollection(AccountPrefix, StringKey, func() *accountv1.Account {
+	return &accountv1.Account{}
+})
 
-	address := account.Address
-	accountBytes, err := proto.Marshal(account)
-	if err != nil {
-		return err
-	}
-
-	key := accountKey(address)
-	return writer.Set(key, accountBytes, nil)
+func (c *ChainStore) StoreAccount(account *accountv1.Account) error {
+	return Accounts.Set(c, account.Address, account)
 }
 
 func (c *ChainStore) GetAccount(address string) (*accountv1.Account, error) {
-	reader := c.reader
-
-	key := accountKey(address)
-	accountBytes, closer, err := reader.Get(key)
+	account, err := Accounts.Get(c, address)
 	if err != nil {
 		return nil, err
 	}
-	defer closer.Close()
+	return account, nil
+}
 
-	account := &accountv1.Account{}
-	err = proto.Unmarshal(accountBytes, account)
-	if err != nil {
-		return nil, err
-	}
+// HasAccount reports whether an account exists at address.
+func (c *ChainStore) HasAccount(address string) (bool, error) {
+	return Accounts.Has(c, address)
+}
 
-	return account, nil
+// IterateAccounts calls fn with every account in address order.
+func (c *ChainStore) IterateAccounts(fn func(account *accountv1.Account) error) error {
+	return Accounts.Iterate(c, func(_ string, account *accountv1.Account) error {
+		return fn(account)
+	})
+}
+
+// ListAccounts returns a page of accounts in address order from cursor on,
+// and the cursor of the next page, or nil after the last page.
+func (c *ChainStore) ListAccounts(cursor []byte, limit int) ([]*accountv1.Account, []byte, error) {
+	return Accounts.Page(c, cursor, limit)
 }
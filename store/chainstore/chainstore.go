@@ -1,7 +1,9 @@
 package chainstore
 
 import (
+	"bytes"
 	"fmt"
+	"sort"
 
 	"github.com/cockroachdb/pebble"
 )
@@ -12,6 +14,22 @@ type ChainStore struct {
 
 	writer pebble.Writer
 	reader pebble.Reader
+
+	// keys written through this batch, folded into the state tree when the
+	// app hash is computed
+	dirty map[string]struct{}
+
+	// set on branches: writes are buffered until Write flushes them to parent
+	parent *ChainStore
+	writes map[string]*[]byte
+
+	// charged for every read and write made through this store, if set
+	gas *GasMeter
+
+	// set on views of a past height returned by AtHeight
+	snapshot   *pebble.Snapshot
+	historical bool
+	version    uint64
 }
 
 func NewChainStore(path string) (*ChainStore, error) {
@@ -19,13 +37,60 @@ func NewChainStore(path string) (*ChainStore, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &ChainStore{db: db, writer: db, reader: db}, nil
+	store := &ChainStore{db: db, writer: db, reader: db}
+	if err := store.CheckSchema(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return store, nil
 }
 
 // Returns a new chain store instance with the writer and reader set to a new batch.
+// The batch is indexed so reads observe writes made earlier in the same block.
 func (c *ChainStore) Batch() *ChainStore {
-	batch := c.db.NewBatch()
-	return &ChainStore{db: c.db, batch: batch, writer: batch, reader: batch}
+	batch := c.db.NewIndexedBatch()
+	return &ChainStore{db: c.db, batch: batch, writer: batch, reader: batch, dirty: make(map[string]struct{})}
+}
+
+// Branch returns a chain store that buffers writes on top of c. Reads see the
+// buffered writes first and fall through to c. Nothing reaches c until Write
+// is called, so discarding the branch rolls its writes back.
+func (c *ChainStore) Branch() *ChainStore {
+	return &ChainStore{db: c.db, parent: c, writes: make(map[string]*[]byte)}
+}
+
+// SetGasMeter charges reads and writes made through c to meter. Writes
+// flushed from a metered branch to its parent are not charged again.
+func (c *ChainStore) SetGasMeter(meter *GasMeter) {
+	c.gas = meter
+}
+
+// Write flushes the writes buffered in a branch to its parent.
+func (c *ChainStore) Write() error {
+	if c.parent == nil {
+		return fmt.Errorf("not a branch")
+	}
+
+	keys := make([]string, 0, len(c.writes))
+	for key := range c.writes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := c.writes[key]
+		var err error
+		if value == nil {
+			err = c.parent.delete([]byte(key))
+		} else {
+			err = c.parent.set([]byte(key), *value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	c.writes = make(map[string]*[]byte)
+	return nil
 }
 
 func (c *ChainStore) Commit() error {
@@ -35,13 +100,152 @@ func (c *ChainStore) Commit() error {
 	return c.batch.Commit(nil)
 }
 
+// RequireBatch returns an error if writes made through c would go straight to
+// the database instead of a batch or branch.
 func (c *ChainStore) RequireBatch() error {
-	if c.batch == nil {
+	if c.batch == nil && c.parent == nil {
 		return fmt.Errorf("batch not started")
 	}
 	return nil
 }
 
+// Close closes the database, or releases the snapshot of a view returned by
+// AtHeight.
 func (c *ChainStore) Close() error {
+	if c.snapshot != nil {
+		return c.snapshot.Close()
+	}
 	return c.db.Close()
 }
+
+// get reads a state key, returning pebble.ErrNotFound if it does not exist.
+// The returned slice is owned by the caller.
+func (c *ChainStore) get(key []byte) ([]byte, error) {
+	value, err := c.read(key)
+	if c.gas != nil {
+		if gasErr := c.gas.Consume(GasReadFlat+GasReadPerByte*uint64(len(value)), "read"); gasErr != nil {
+			return nil, gasErr
+		}
+	}
+	return value, err
+}
+
+// read is get without gas accounting.
+func (c *ChainStore) read(key []byte) ([]byte, error) {
+	if c.historical {
+		return c.readAt(key)
+	}
+	if c.parent != nil {
+		if value, ok := c.writes[string(key)]; ok {
+			if value == nil {
+				return nil, pebble.ErrNotFound
+			}
+			return bytes.Clone(*value), nil
+		}
+		return c.parent.get(key)
+	}
+
+	value, closer, err := c.reader.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+	return bytes.Clone(value), nil
+}
+
+// iterate calls fn with every key and value under prefix in key order. It
+// reads the store's batch or database directly, so it cannot be used on a
+// branch. The slices passed to fn are only valid until it returns.
+func (c *ChainStore) iterate(prefix []byte, fn func(key, value []byte) error) error {
+	iter, err := c.newIter(prefix, prefix)
+	if err != nil {
+		return err
+	}
+	for iter.First(); iter.Valid(); iter.Next() {
+		value, err := iter.ValueAndErr()
+		if err != nil {
+			iter.Close()
+			return err
+		}
+		if err := fn(iter.Key(), value); err != nil {
+			iter.Close()
+			return err
+		}
+	}
+	return iter.Close()
+}
+
+// newIter returns an iterator over the keys under prefix from start on.
+func (c *ChainStore) newIter(prefix, start []byte) (*pebble.Iterator, error) {
+	if c.parent != nil {
+		return nil, fmt.Errorf("cannot iterate a branch")
+	}
+	if c.historical {
+		return nil, fmt.Errorf("cannot iterate state at a past height")
+	}
+	return c.reader.NewIter(&pebble.IterOptions{LowerBound: start, UpperBound: prefixEnd(prefix)})
+}
+
+// prefixEnd returns the first key after every key starting with prefix, or
+// nil if there is none.
+func prefixEnd(prefix []byte) []byte {
+	end := bytes.Clone(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return nil
+}
+
+// set writes a state key and marks it for inclusion in the app hash.
+func (c *ChainStore) set(key, value []byte) error {
+	if err := c.RequireBatch(); err != nil {
+		return err
+	}
+	if c.gas != nil {
+		if err := c.gas.Consume(GasWriteFlat+GasWritePerByte*uint64(len(key)+len(value)), "write"); err != nil {
+			return err
+		}
+	}
+	if c.parent != nil {
+		value = bytes.Clone(value)
+		c.writes[string(key)] = &value
+		return nil
+	}
+	if err := c.writer.Set(key, value, nil); err != nil {
+		return err
+	}
+	c.dirty[string(key)] = struct{}{}
+	return nil
+}
+
+// delete removes a state key and marks it for inclusion in the app hash.
+func (c *ChainStore) delete(key []byte) error {
+	if err := c.RequireBatch(); err != nil {
+		return err
+	}
+	if c.gas != nil {
+		if err := c.gas.Consume(GasDelete, "delete"); err != nil {
+			return err
+		}
+	}
+	return c.remove(key)
+}
+
+// remove is delete without gas accounting.
+func (c *ChainStore) remove(key []byte) error {
+	if err := c.RequireBatch(); err != nil {
+		return err
+	}
+	if c.parent != nil {
+		c.writes[string(key)] = nil
+		return nil
+	}
+	if err := c.writer.Delete(key, nil); err != nil {
+		return err
+	}
+	c.dirty[string(key)] = struct{}{}
+	return nil
+}
@@ -0,0 +1,323 @@
+package chainstore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+
+	"github.com/cockroachdb/pebble"
+	"google.golang.org/protobuf/proto"
+)
+
+// KeyCodec encodes the keys of a collection or index. Encodings must sort
+// like the keys they encode for iteration to be in key order.
+type KeyCodec[K any] interface {
+	Encode(key K) []byte
+	Decode(b []byte) (K, error)
+}
+
+type stringKey struct{}
+
+func (stringKey) Encode(key string) []byte        { return []byte(key) }
+func (stringKey) Decode(b []byte) (string, error) { return string(b), nil }
+
+// StringKey encodes string keys as their bytes.
+var StringKey KeyCodec[string] = stringKey{}
+
+// marshalOptions keep encodings stable across nodes, including map fields.
+var marshalOptions = proto.MarshalOptions{Deterministic: true}
+
+// Indexer is a secondary index kept up to date by the collection it is
+// declared on.
+type Indexer[K any, V proto.Message] interface {
+	add(store *ChainStore, key K, value V) error
+	remove(store *ChainStore, key K, value V) error
+}
+
+// Collection stores proto records of type V under a prefix, keyed by K.
+// Collections are declared once and used with whichever store a caller
+// holds, so writes land in its batch or branch and count towards its gas.
+type Collection[K any, V proto.Message] struct {
+	prefix   []byte
+	keys     KeyCodec[K]
+	newValue func() V
+	indexes  []Indexer[K, V]
+}
+
+// NewCollection declares a collection under prefix. newValue returns an
+// empty record to decode into. The indexes are updated on every Set and
+// Delete.
+func NewCollection[K any, V proto.Message](prefix string, keys KeyCodec[K], newValue func() V, indexes ...Indexer[K, V]) *Collection[K, V] {
+	return &Collection[K, V]{prefix: []byte(prefix), keys: keys, newValue: newValue, indexes: indexes}
+}
+
+// Key returns the state key of the record at key.
+func (col *Collection[K, V]) Key(key K) []byte {
+	return append(bytes.Clone(col.prefix), col.keys.Encode(key)...)
+}
+
+// Get returns the record at key, or pebble.ErrNotFound.
+func (col *Collection[K, V]) Get(store *ChainStore, key K) (V, error) {
+	return col.decode(store.get(col.Key(key)))
+}
+
+func (col *Collection[K, V]) decode(data []byte, err error) (V, error) {
+	value := col.newValue()
+	if err != nil {
+		return value, err
+	}
+	if err := proto.Unmarshal(data, value); err != nil {
+		return value, err
+	}
+	return value, nil
+}
+
+// Has reports whether a record exists at key.
+func (col *Collection[K, V]) Has(store *ChainStore, key K) (bool, error) {
+	_, err := store.get(col.Key(key))
+	if errors.Is(err, pebble.ErrNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Set writes the record at key, moving its index entries from the record it
+// replaces. The record and the index entries it is written under are charged
+// gas like any write. The reads and removals made to move entries from the
+// replaced record are not, so rewriting a record costs the same as writing it.
+func (col *Collection[K, V]) Set(store *ChainStore, key K, value V) error {
+	old, replaced, err := col.replaced(store, key)
+	if err != nil {
+		return err
+	}
+
+	data, err := marshalOptions.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if err := store.set(col.Key(key), data); err != nil {
+		return err
+	}
+
+	for _, index := range col.indexes {
+		if replaced {
+			if err := index.remove(store, key, old); err != nil {
+				return err
+			}
+		}
+		if err := index.add(store, key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete removes the record at key and its index entries.
+func (col *Collection[K, V]) Delete(store *ChainStore, key K) error {
+	old, replaced, err := col.replaced(store, key)
+	if err != nil {
+		return err
+	}
+	if err := store.delete(col.Key(key)); err != nil {
+		return err
+	}
+	if !replaced {
+		return nil
+	}
+	for _, index := range col.indexes {
+		if err := index.remove(store, key, old); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// replaced returns the stored record at key whose index entries a write
+// replaces, and false if there is none or the collection has no indexes.
+func (col *Collection[K, V]) replaced(store *ChainStore, key K) (V, bool, error) {
+	if len(col.indexes) == 0 {
+		var zero V
+		return zero, false, nil
+	}
+	old, err := col.decode(store.read(col.Key(key)))
+	if errors.Is(err, pebble.ErrNotFound) {
+		return old, false, nil
+	} else if err != nil {
+		return old, false, err
+	}
+	return old, true, nil
+}
+
+// Iterate calls fn with every record in key order.
+func (col *Collection[K, V]) Iterate(store *ChainStore, fn func(key K, value V) error) error {
+	return store.iterate(col.prefix, func(k, data []byte) error {
+		key, err := col.keys.Decode(k[len(col.prefix):])
+		if err != nil {
+			return err
+		}
+		value := col.newValue()
+		if err := proto.Unmarshal(data, value); err != nil {
+			return err
+		}
+		return fn(key, value)
+	})
+}
+
+// Page returns a page of the records from cursor on in key order, and the
+// cursor of the next page, or nil after the last page. Listings of the
+// records sharing a field page through an index instead.
+func (col *Collection[K, V]) Page(store *ChainStore, cursor []byte, limit int) ([]V, []byte, error) {
+	var values []V
+	next, err := store.IteratePage(col.prefix, cursor, limit, func(_, data []byte) (bool, error) {
+		value := col.newValue()
+		if err := proto.Unmarshal(data, value); err != nil {
+			return false, err
+		}
+		values = append(values, value)
+		return true, nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return values, next, nil
+}
+
+// UniqueIndex maps a field of each record to the record's key. A record
+// written with a field another record is indexed under takes the entry over,
+// so callers that need the field to be unique check Get before writing.
+type UniqueIndex[I, K any, V proto.Message] struct {
+	prefix   []byte
+	keys     KeyCodec[I]
+	primary  KeyCodec[K]
+	indexKey func(value V) (I, bool)
+}
+
+// NewUniqueIndex declares a unique index under prefix. indexKey returns the
+// indexed field of a record, or false to leave the record out of the index.
+// The index entry holds the record's encoded key.
+func NewUniqueIndex[I, K any, V proto.Message](prefix string, keys KeyCodec[I], primary KeyCodec[K], indexKey func(value V) (I, bool)) *UniqueIndex[I, K, V] {
+	return &UniqueIndex[I, K, V]{prefix: []byte(prefix), keys: keys, primary: primary, indexKey: indexKey}
+}
+
+// Key returns the state key of the index entry for an indexed field.
+func (idx *UniqueIndex[I, K, V]) Key(indexKey I) []byte {
+	return append(bytes.Clone(idx.prefix), idx.keys.Encode(indexKey)...)
+}
+
+// Get returns the key of the record indexed under indexKey, or
+// pebble.ErrNotFound.
+func (idx *UniqueIndex[I, K, V]) Get(store *ChainStore, indexKey I) (K, error) {
+	data, err := store.get(idx.Key(indexKey))
+	if err != nil {
+		var zero K
+		return zero, err
+	}
+	return idx.primary.Decode(data)
+}
+
+func (idx *UniqueIndex[I, K, V]) add(store *ChainStore, key K, value V) error {
+	indexKey, ok := idx.indexKey(value)
+	if !ok {
+		return nil
+	}
+	return store.set(idx.Key(indexKey), idx.primary.Encode(key))
+}
+
+// remove removes the entry of a record unless another record has taken it
+// over since.
+func (idx *UniqueIndex[I, K, V]) remove(store *ChainStore, key K, value V) error {
+	indexKey, ok := idx.indexKey(value)
+	if !ok {
+		return nil
+	}
+	entry := idx.Key(indexKey)
+	existing, err := store.read(entry)
+	if errors.Is(err, pebble.ErrNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if !bytes.Equal(existing, idx.primary.Encode(key)) {
+		return nil
+	}
+	return store.remove(entry)
+}
+
+// MultiIndex groups records by a field that many records may share.
+type MultiIndex[I, K any, V proto.Message] struct {
+	prefix   []byte
+	keys     KeyCodec[I]
+	primary  KeyCodec[K]
+	indexKey func(value V) (I, bool)
+}
+
+// NewMultiIndex declares an index under prefix grouping records by the field
+// indexKey returns, or leaving a record out if it returns false. Each record
+// has an empty entry keyed by the field and the record's key.
+func NewMultiIndex[I, K any, V proto.Message](prefix string, keys KeyCodec[I], primary KeyCodec[K], indexKey func(value V) (I, bool)) *MultiIndex[I, K, V] {
+	return &MultiIndex[I, K, V]{prefix: []byte(prefix), keys: keys, primary: primary, indexKey: indexKey}
+}
+
+// groupPrefix returns the prefix of the entries for an indexed field. The
+// field is length prefixed so one group never runs into another.
+func (idx *MultiIndex[I, K, V]) groupPrefix(indexKey I) []byte {
+	encoded := idx.keys.Encode(indexKey)
+	prefix := binary.BigEndian.AppendUint32(bytes.Clone(idx.prefix), uint32(len(encoded)))
+	return append(prefix, encoded...)
+}
+
+// Key returns the state key of the index entry of a record.
+func (idx *MultiIndex[I, K, V]) Key(indexKey I, key K) []byte {
+	return append(idx.groupPrefix(indexKey), idx.primary.Encode(key)...)
+}
+
+// Iterate calls fn with the key of every record indexed under indexKey, in
+// key order.
+func (idx *MultiIndex[I, K, V]) Iterate(store *ChainStore, indexKey I, fn func(key K) error) error {
+	prefix := idx.groupPrefix(indexKey)
+	return store.iterate(prefix, func(k, _ []byte) error {
+		key, err := idx.primary.Decode(k[len(prefix):])
+		if err != nil {
+			return err
+		}
+		return fn(key)
+	})
+}
+
+// Page returns a page of the keys of the records indexed under indexKey from
+// cursor on in key order, and the cursor of the next page, or nil after the
+// last page.
+func (idx *MultiIndex[I, K, V]) Page(store *ChainStore, indexKey I, cursor []byte, limit int) ([]K, []byte, error) {
+	prefix := idx.groupPrefix(indexKey)
+	var keys []K
+	next, err := store.IteratePage(prefix, cursor, limit, func(k, _ []byte) (bool, error) {
+		key, err := idx.primary.Decode(k[len(prefix):])
+		if err != nil {
+			return false, err
+		}
+		keys = append(keys, key)
+		return true, nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return keys, next, nil
+}
+
+func (idx *MultiIndex[I, K, V]) add(store *ChainStore, key K, value V) error {
+	indexKey, ok := idx.indexKey(value)
+	if !ok {
+		return nil
+	}
+	return store.set(idx.Key(indexKey, key), nil)
+}
+
+func (idx *MultiIndex[I, K, V]) remove(store *ChainStore, key K, value V) error {
+	indexKey, ok := idx.indexKey(value)
+	if !ok {
+		return nil
+	}
+	return store.remove(idx.Key(indexKey, key))
+}
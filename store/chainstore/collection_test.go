@@ -0,0 +1,257 @@
+package chainstore
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/cockroachdb/pebble"
+	storagev1 "github.com/sonata-labs/sonata/gen/storage/v1"
+)
+
+// newTestBatch returns a batch on a new chain store.
+func newTestBatch(t *testing.T) *ChainStore {
+	t.Helper()
+	store, err := NewChainStore(filepath.Join(t.TempDir(), "chainstore"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store.Batch()
+}
+
+func upload(cid, original, uploader string) *storagev1.FileUploadMessage {
+	return &storagev1.FileUploadMessage{TranscodedCid: cid, OriginalCid: original, UploaderAddress: uploader}
+}
+
+// op writes an upload, or deletes the upload at its transcoded CID if only
+// that is set.
+type op = *storagev1.FileUploadMessage
+
+func TestCollectionIndexes(t *testing.T) {
+	tests := []struct {
+		name string
+		ops  []op
+
+		uploads   []string            // transcoded CIDs stored
+		originals map[string]string   // original CID to transcoded CID
+		uploaders map[string][]string // uploader to transcoded CIDs
+	}{
+		{
+			name:      "writes",
+			ops:       []op{upload("t1", "o1", "alice"), upload("t2", "o2", "alice"), upload("t3", "o3", "bob")},
+			uploads:   []string{"t1", "t2", "t3"},
+			originals: map[string]string{"o1": "t1", "o2": "t2", "o3": "t3"},
+			uploaders: map[string][]string{"alice": {"t1", "t2"}, "bob": {"t3"}},
+		},
+		{
+			name:      "rewrite moves index entries",
+			ops:       []op{upload("t1", "o1", "alice"), upload("t1", "o9", "bob")},
+			uploads:   []string{"t1"},
+			originals: map[string]string{"o9": "t1"},
+			uploaders: map[string][]string{"bob": {"t1"}},
+		},
+		{
+			name:      "delete removes index entries",
+			ops:       []op{upload("t1", "o1", "alice"), upload("t2", "o2", "alice"), {TranscodedCid: "t1"}},
+			uploads:   []string{"t2"},
+			originals: map[string]string{"o2": "t2"},
+			uploaders: map[string][]string{"alice": {"t2"}},
+		},
+		{
+			name:      "delete of a missing record",
+			ops:       []op{{TranscodedCid: "t1"}},
+			originals: map[string]string{},
+			uploaders: map[string][]string{},
+		},
+		{
+			name:      "unique entry taken over is kept when the old record goes",
+			ops:       []op{upload("t1", "o1", "alice"), upload("t2", "o1", "bob"), {TranscodedCid: "t1"}},
+			uploads:   []string{"t2"},
+			originals: map[string]string{"o1": "t2"},
+			uploaders: map[string][]string{"bob": {"t2"}},
+		},
+		{
+			name:      "records left out of an index",
+			ops:       []op{upload("t1", "o1", ""), upload("t2", "", "alice")},
+			uploads:   []string{"t1", "t2"},
+			originals: map[string]string{"o1": "t1", "": "t2"},
+			uploaders: map[string][]string{"alice": {"t2"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newTestBatch(t)
+			for _, o := range tt.ops {
+				var err error
+				if o.OriginalCid == "" && o.UploaderAddress == "" {
+					err = Uploads.Delete(store, o.TranscodedCid)
+				} else {
+					err = Uploads.Set(store, o.TranscodedCid, o)
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var uploads []string
+			if err := Uploads.Iterate(store, func(cid string, _ *storagev1.FileUploadMessage) error {
+				uploads = append(uploads, cid)
+				return nil
+			}); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(uploads, tt.uploads) {
+				t.Errorf("uploads %v, want %v", uploads, tt.uploads)
+			}
+
+			for original, want := range tt.originals {
+				if got, err := UploadsByOriginalCID.Get(store, original); err != nil || got != want {
+					t.Errorf("original %q indexes %q (%v), want %q", original, got, err, want)
+				}
+			}
+			for uploader, want := range tt.uploaders {
+				var got []string
+				if err := UploadsByUploader.Iterate(store, uploader, func(cid string) error {
+					got = append(got, cid)
+					return nil
+				}); err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("uploader %s indexes %v, want %v", uploader, got, want)
+				}
+			}
+
+			// no entries are left behind besides the expected ones
+			var entries int
+			if err := store.iterate([]byte("upload_idx/"), func(_, _ []byte) error {
+				entries++
+				return nil
+			}); err != nil {
+				t.Fatal(err)
+			}
+			want := len(tt.originals)
+			for _, cids := range tt.uploaders {
+				want += len(cids)
+			}
+			if entries != want {
+				t.Errorf("%d index entries, want %d", entries, want)
+			}
+		})
+	}
+}
+
+func TestCollectionSetGas(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing *storagev1.FileUploadMessage
+	}{
+		{"new record", nil},
+		{"same record", upload("t1", "o1", "alice")},
+		{"moved index fields", upload("t1", "o0", "bob")},
+		{"record left out of the indexes", upload("t1", "", "")},
+	}
+
+	// writing a record costs the same whatever it replaces
+	var want uint64
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newTestBatch(t)
+			if tt.existing != nil {
+				if err := Uploads.Set(store, "t1", tt.existing); err != nil {
+					t.Fatal(err)
+				}
+			}
+			meter := NewGasMeter(1_000_000)
+			store.SetGasMeter(meter)
+			if err := Uploads.Set(store, "t1", upload("t1", "o1", "alice")); err != nil {
+				t.Fatal(err)
+			}
+			if i == 0 {
+				want = meter.Used()
+			} else if meter.Used() != want {
+				t.Errorf("gas %d, want %d", meter.Used(), want)
+			}
+		})
+	}
+}
+
+func TestCollectionGetMissing(t *testing.T) {
+	store := newTestBatch(t)
+	if _, err := Uploads.Get(store, "missing"); !errors.Is(err, pebble.ErrNotFound) {
+		t.Errorf("Get: got %v, want pebble.ErrNotFound", err)
+	}
+	if ok, err := Uploads.Has(store, "missing"); err != nil || ok {
+		t.Errorf("Has = %v, %v, want false", ok, err)
+	}
+	if _, err := UploadsByOriginalCID.Get(store, "missing"); !errors.Is(err, pebble.ErrNotFound) {
+		t.Errorf("index Get: got %v, want pebble.ErrNotFound", err)
+	}
+}
+
+func TestCollectionPages(t *testing.T) {
+	store := newTestBatch(t)
+	var all, alice []string
+	for i := 0; i < 7; i++ {
+		cid := fmt.Sprintf("t%d", i)
+		uploader := "bob"
+		if i%2 == 0 {
+			uploader = "alice"
+			alice = append(alice, cid)
+		}
+		all = append(all, cid)
+		if err := Uploads.Set(store, cid, upload(cid, "o"+cid, uploader)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, limit := range []int{1, 2, 3, 7, 10} {
+		t.Run(fmt.Sprintf("limit %d", limit), func(t *testing.T) {
+			var got []string
+			var cursor []byte
+			for pages := 0; ; pages++ {
+				if pages > len(all) {
+					t.Fatal("paging does not end")
+				}
+				values, next, err := Uploads.Page(store, cursor, limit)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(values) > limit {
+					t.Fatalf("page of %d records, limit %d", len(values), limit)
+				}
+				for _, v := range values {
+					got = append(got, v.TranscodedCid)
+				}
+				if cursor = next; cursor == nil {
+					break
+				}
+			}
+			if !reflect.DeepEqual(got, all) {
+				t.Errorf("collection pages %v, want %v", got, all)
+			}
+
+			got = nil
+			cursor = nil
+			for pages := 0; ; pages++ {
+				if pages > len(alice) {
+					t.Fatal("paging does not end")
+				}
+				keys, next, err := UploadsByUploader.Page(store, "alice", cursor, limit)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, keys...)
+				if cursor = next; cursor == nil {
+					break
+				}
+			}
+			if !reflect.DeepEqual(got, alice) {
+				t.Errorf("index pages %v, want %v", got, alice)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package chainstore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/sonata-labs/sonata/store/chainstore/smt"
+)
+
+// Tree nodes and roots are bookkeeping for the state commitment and are not
+// part of the committed state themselves.
+const (
+	TreeNodePrefix = "tree/node/"
+	TreeRootPrefix = "tree/root/"
+	TreeLatestKey  = "tree/latest"
+)
+
+func treeNodeKey(ref smt.Ref) []byte {
+	return append([]byte(TreeNodePrefix), ref.Bytes()...)
+}
+
+func treeRootKey(version uint64) []byte {
+	key := []byte(TreeRootPrefix)
+	return binary.BigEndian.AppendUint64(key, version)
+}
+
+// nodeStore adapts the chain store reader and writer to the tree.
+type nodeStore struct {
+	reader pebble.Reader
+	writer pebble.Writer
+}
+
+func (s *nodeStore) GetNode(ref smt.Ref) ([]byte, error) {
+	data, closer, err := s.reader.Get(treeNodeKey(ref))
+	if errors.Is(err, pebble.ErrNotFound) {
+		return nil, smt.ErrNodeNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+	return bytes.Clone(data), nil
+}
+
+func (s *nodeStore) SetNode(ref smt.Ref, node []byte) error {
+	return s.writer.Set(treeNodeKey(ref), node, nil)
+}
+
+// latestRoot returns the most recent tree root and its version. An empty
+// store has the empty root at version 0.
+func (c *ChainStore) latestRoot() (smt.Ref, uint64, error) {
+	data, closer, err := c.reader.Get([]byte(TreeLatestKey))
+	if errors.Is(err, pebble.ErrNotFound) {
+		return smt.Ref{}, 0, nil
+	} else if err != nil {
+		return smt.Ref{}, 0, err
+	}
+	if len(data) != 8 {
+		closer.Close()
+		return smt.Ref{}, 0, fmt.Errorf("malformed latest tree version")
+	}
+	version := binary.BigEndian.Uint64(data)
+	closer.Close()
+
+	root, err := c.rootAt(version)
+	return root, version, err
+}
+
+// rootAt returns the tree root committed at version.
+func (c *ChainStore) rootAt(version uint64) (smt.Ref, error) {
+	data, closer, err := c.reader.Get(treeRootKey(version))
+	if err != nil {
+		return smt.Ref{}, fmt.Errorf("tree root at %d: %w", version, err)
+	}
+	defer closer.Close()
+	return smt.DecodeRef(data)
+}
+
+// stateRoot returns the tree root of the state c reads: the view's height,
+// or the latest.
+func (c *ChainStore) stateRoot() (smt.Ref, error) {
+	if c.historical {
+		return c.rootAt(c.version)
+	}
+	root, _, err := c.latestRoot()
+	return root, err
+}
+
+// AppHash returns the root hash of the most recently computed state tree.
+func (c *ChainStore) AppHash() ([]byte, error) {
+	root, _, err := c.latestRoot()
+	if err != nil {
+		return nil, err
+	}
+	return root.Hash[:], nil
+}
+
+// ComputeAppHash folds every state key written through this batch into the
+// state tree at the given height and returns the new root hash. Height 0 is
+// the genesis state of a chain starting at height 1. Tree nodes
+// and the root are written to the batch, so they become durable with Commit.
+func (c *ChainStore) ComputeAppHash(height int64) ([]byte, error) {
+	if c.batch == nil {
+		return nil, fmt.Errorf("batch not started")
+	}
+	if height < 0 {
+		return nil, fmt.Errorf("invalid height %d", height)
+	}
+	version := uint64(height)
+
+	root, latest, err := c.latestRoot()
+	if err != nil {
+		return nil, err
+	}
+	// genesis state is committed at the version before the initial height,
+	// which is 0 for most chains and the same as an empty tree
+	if latest > version || (latest == version && version > 0) {
+		return nil, fmt.Errorf("state tree already at version %d, cannot write %d", latest, version)
+	}
+
+	keys := make([]string, 0, len(c.dirty))
+	for key := range c.dirty {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	entries := make([]smt.Entry, 0, len(keys))
+	for _, key := range keys {
+		entry := smt.Entry{Path: smt.Path([]byte(key))}
+		value, closer, err := c.reader.Get([]byte(key))
+		switch {
+		case errors.Is(err, pebble.ErrNotFound):
+			entry.Delete = true
+		case err != nil:
+			return nil, err
+		default:
+			entry.ValueHash = smt.ValueHash(value)
+			closer.Close()
+		}
+		entries = append(entries, entry)
+	}
+
+	tree := smt.NewTree(&nodeStore{reader: c.reader, writer: c.writer})
+	root, err = tree.Update(root, version, entries)
+	if err != nil {
+		return nil, fmt.Errorf("updating state tree: %w", err)
+	}
+	if err := c.recordHistory(version, keys, tree.Orphans()); err != nil {
+		return nil, fmt.Errorf("recording state history: %w", err)
+	}
+
+	if err := c.writer.Set(treeRootKey(version), root.Bytes(), nil); err != nil {
+		return nil, err
+	}
+	if err := c.writer.Set([]byte(TreeLatestKey), binary.BigEndian.AppendUint64(nil, version), nil); err != nil {
+		return nil, err
+	}
+	c.dirty = make(map[string]struct{})
+
+	return root.Hash[:], nil
+}
+
+// AppHashAt returns the root hash of the state tree computed at height, and
+// false if no tree was committed at that height.
+func (c *ChainStore) AppHashAt(height int64) ([]byte, bool, error) {
+	if height < 0 {
+		return nil, false, fmt.Errorf("invalid height %d", height)
+	}
+	root, err := c.rootAt(uint64(height))
+	if errors.Is(err, pebble.ErrNotFound) {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+	return root.Hash[:], true, nil
+}
@@ -0,0 +1,81 @@
+package chainstore
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	DDEXPrefix = "ddex/"
+)
+
+// Kinds of DDEX messages recorded on chain. Each kind is keyed by the
+// account that submitted the message and the MessageId from its header.
+const (
+	DDEXNewRelease   = "new_release"
+	DDEXCatalogList  = "catalog_list"
+	DDEXPurgeRelease = "purge_release"
+	DDEXPie          = "pie"
+	DDEXPieRequest   = "pie_request"
+	DDEXMead         = "mead"
+)
+
+// DDEXMessageID identifies a DDEX message on chain. DDEX only makes a
+// MessageId unique within the systems of its sender, so messages are scoped
+// by the address of the account that submitted them, and one account cannot
+// take the ids of another.
+type DDEXMessageID struct {
+	Address   string
+	MessageID string
+}
+
+type ddexMessageKey struct{}
+
+// Addresses are hex, so the first separator ends the address.
+func (ddexMessageKey) Encode(id DDEXMessageID) []byte {
+	return []byte(id.Address + "/" + id.MessageID)
+}
+
+func (ddexMessageKey) Decode(b []byte) (DDEXMessageID, error) {
+	address, messageID, ok := strings.Cut(string(b), "/")
+	if !ok {
+		return DDEXMessageID{}, fmt.Errorf("malformed ddex message key %q", b)
+	}
+	return DDEXMessageID{Address: address, MessageID: messageID}, nil
+}
+
+// DDEXMessages returns the collection of DDEX messages of a kind. Messages
+// read from it are decoded into ones returned by newMsg.
+func DDEXMessages(kind string, newMsg func() proto.Message) *Collection[DDEXMessageID, proto.Message] {
+	return NewCollection[DDEXMessageID](DDEXPrefix+kind+"/", ddexMessageKey{}, newMsg)
+}
+
+// DDEXMessageKey returns the state key of a DDEX message.
+func DDEXMessageKey(kind string, id DDEXMessageID) []byte {
+	return DDEXMessages(kind, nil).Key(id)
+}
+
+// StoreDDEXMessage stores a DDEX message of the given kind.
+func (c *ChainStore) StoreDDEXMessage(kind string, id DDEXMessageID, msg proto.Message) error {
+	return DDEXMessages(kind, nil).Set(c, id, msg)
+}
+
+// GetDDEXMessage reads a DDEX message of the given kind into msg.
+func (c *ChainStore) GetDDEXMessage(kind string, id DDEXMessageID, msg proto.Message) error {
+	_, err := DDEXMessages(kind, func() proto.Message { return msg }).Get(c, id)
+	return err
+}
+
+// HasDDEXMessage reports whether a DDEX message of the given kind exists.
+func (c *ChainStore) HasDDEXMessage(kind string, id DDEXMessageID) (bool, error) {
+	return DDEXMessages(kind, nil).Has(c, id)
+}
+
+// IterateDDEXMessages calls fn with every DDEX message of the given kind in
+// address and message id order, each decoded into a message returned by
+// newMsg.
+func (c *ChainStore) IterateDDEXMessages(kind string, newMsg func() proto.Message, fn func(id DDEXMessageID, msg proto.Message) error) error {
+	return DDEXMessages(kind, newMsg).Iterate(c, fn)
+}
@@ -0,0 +1,196 @@
+package chainstore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/sonata-labs/sonata/store/chainstore/smt"
+)
+
+// ErrAppHashMismatch is returned when imported state does not hash to the app
+// hash it was expected to.
+var ErrAppHashMismatch = errors.New("app hash mismatch")
+
+// bookkeepingPrefixes are the prefixes of the keys kept alongside the state
+// that are not part of it: the state tree, its history and block metadata.
+var bookkeepingPrefixes = []string{"tree/", "history/", "meta/"}
+
+// IsStateKey reports whether key is part of the committed state, rather than
+// bookkeeping kept alongside it.
+func IsStateKey(key []byte) bool {
+	for _, prefix := range bookkeepingPrefixes {
+		if strings.HasPrefix(string(key), prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// IterateState calls fn with every state key in key order, skipping the
+// bookkeeping kept alongside it. The key passed to fn is only valid until it
+// returns.
+func (c *ChainStore) IterateState(fn func(key []byte) error) error {
+	return c.iterate(nil, func(key, value []byte) error {
+		if !IsStateKey(key) {
+			return nil
+		}
+		return fn(key)
+	})
+}
+
+// StateExport is a consistent read of the state committed at one height,
+// unaffected by the blocks committed after it.
+type StateExport struct {
+	snapshot *pebble.Snapshot
+
+	Height  int64
+	AppHash []byte
+}
+
+// ExportState returns an export of the state of the last committed block.
+// The export holds a snapshot of the database and must be closed.
+func (c *ChainStore) ExportState() (*StateExport, error) {
+	snapshot := c.db.NewSnapshot()
+	view := &ChainStore{db: c.db, reader: snapshot}
+	height, appHash, err := view.GetLastBlock()
+	if err != nil {
+		snapshot.Close()
+		return nil, err
+	}
+	if height == 0 {
+		snapshot.Close()
+		return nil, fmt.Errorf("no block committed")
+	}
+	return &StateExport{snapshot: snapshot, Height: height, AppHash: appHash}, nil
+}
+
+// Iterate calls fn with every state key and value in key order. The slices
+// passed to fn are only valid until it returns.
+func (e *StateExport) Iterate(fn func(key, value []byte) error) error {
+	iter, err := e.snapshot.NewIter(nil)
+	if err != nil {
+		return err
+	}
+	for iter.First(); iter.Valid(); iter.Next() {
+		if !IsStateKey(iter.Key()) {
+			continue
+		}
+		value, err := iter.ValueAndErr()
+		if err != nil {
+			iter.Close()
+			return err
+		}
+		if err := fn(iter.Key(), value); err != nil {
+			iter.Close()
+			return err
+		}
+	}
+	return iter.Close()
+}
+
+// Close releases the export's snapshot.
+func (e *StateExport) Close() error {
+	return e.snapshot.Close()
+}
+
+// StateImport restores the state committed at a height into an empty store.
+// Nothing is written until Commit checks the imported state against the
+// block's app hash.
+type StateImport struct {
+	db      *pebble.DB
+	batch   *pebble.Batch
+	height  int64
+	entries []smt.Entry
+	last    []byte
+}
+
+// ImportState starts restoring the state committed at height. The store must
+// not have committed any block.
+func (c *ChainStore) ImportState(height int64) (*StateImport, error) {
+	if height <= 0 {
+		return nil, fmt.Errorf("invalid height %d", height)
+	}
+	last, _, err := c.GetLastBlock()
+	if err != nil {
+		return nil, err
+	}
+	if last != 0 {
+		return nil, fmt.Errorf("chain store already has state at height %d", last)
+	}
+	return &StateImport{db: c.db, batch: c.db.NewIndexedBatch(), height: height}, nil
+}
+
+// Add imports a state key. Keys must be added in increasing order.
+func (i *StateImport) Add(key, value []byte) error {
+	if !IsStateKey(key) {
+		return fmt.Errorf("%q is not a state key", key)
+	}
+	if i.last != nil && bytes.Compare(key, i.last) <= 0 {
+		return fmt.Errorf("state key %q out of order", key)
+	}
+	i.last = bytes.Clone(key)
+
+	// state written by a newer binary could not be read after the restore
+	if string(key) == SchemaVersionKey {
+		version, err := decodeSchemaVersion(value)
+		if err != nil {
+			return err
+		}
+		if version > LatestSchemaVersion() {
+			return fmt.Errorf("%w: state is at version %d, binary at %d", ErrSchemaTooNew, version, LatestSchemaVersion())
+		}
+	}
+
+	if err := i.batch.Set(key, value, nil); err != nil {
+		return err
+	}
+	i.entries = append(i.entries, smt.Entry{Path: smt.Path(key), ValueHash: smt.ValueHash(value)})
+	return nil
+}
+
+// Commit builds the state tree of the imported keys and, if its root matches
+// appHash, writes the state as the store's last committed block. State before
+// the height cannot be read from the restored store.
+func (i *StateImport) Commit(appHash []byte) error {
+	defer i.Discard()
+
+	version := uint64(i.height)
+	tree := smt.NewTree(&nodeStore{reader: i.batch, writer: i.batch})
+	root, err := tree.Update(smt.Ref{}, version, i.entries)
+	if err != nil {
+		return fmt.Errorf("building state tree: %w", err)
+	}
+	if !bytes.Equal(root.Hash[:], appHash) {
+		return fmt.Errorf("%w: imported state hashes to %X, expected %X", ErrAppHashMismatch, root.Hash[:], appHash)
+	}
+
+	if err := i.batch.Set(treeRootKey(version), root.Bytes(), nil); err != nil {
+		return err
+	}
+	if err := i.batch.Set([]byte(TreeLatestKey), binary.BigEndian.AppendUint64(nil, version), nil); err != nil {
+		return err
+	}
+	if err := i.batch.Set([]byte(HistoryEarliestKey), binary.BigEndian.AppendUint64(nil, version), nil); err != nil {
+		return err
+	}
+	if err := i.batch.Set([]byte(LastBlockHeightKey), binary.BigEndian.AppendUint64(nil, version), nil); err != nil {
+		return err
+	}
+	if err := i.batch.Set([]byte(LastBlockAppHashKey), appHash, nil); err != nil {
+		return err
+	}
+	return i.batch.Commit(pebble.Sync)
+}
+
+// Discard drops the imported keys without writing them.
+func (i *StateImport) Discard() {
+	if i.batch != nil {
+		i.batch.Close()
+		i.batch = nil
+	}
+	i.entries = nil
+}
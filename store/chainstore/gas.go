@@ -0,0 +1,57 @@
+package chainstore
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Gas charged for chain store access by a metered store.
+const (
+	GasReadFlat     uint64 = 1000
+	GasReadPerByte  uint64 = 3
+	GasWriteFlat    uint64 = 2000
+	GasWritePerByte uint64 = 30
+	GasDelete       uint64 = 1000
+)
+
+var ErrOutOfGas = errors.New("out of gas")
+
+// GasMeter tracks the gas consumed by a transaction against its limit.
+type GasMeter struct {
+	limit    uint64
+	consumed uint64
+}
+
+func NewGasMeter(limit uint64) *GasMeter {
+	return &GasMeter{limit: limit}
+}
+
+// Consume charges amount to the meter. Once the limit is exceeded every call
+// returns ErrOutOfGas, and the transaction must fail even if a handler
+// ignores the error.
+func (g *GasMeter) Consume(amount uint64, descriptor string) error {
+	if amount > math.MaxUint64-g.consumed {
+		g.consumed = math.MaxUint64
+	} else {
+		g.consumed += amount
+	}
+	if g.IsExceeded() {
+		return fmt.Errorf("%w: %s, limit %d", ErrOutOfGas, descriptor, g.limit)
+	}
+	return nil
+}
+
+// IsExceeded reports whether more gas was consumed than the limit allows.
+func (g *GasMeter) IsExceeded() bool {
+	return g.consumed > g.limit
+}
+
+// Used returns the gas consumed, capped at the limit.
+func (g *GasMeter) Used() uint64 {
+	return min(g.consumed, g.limit)
+}
+
+func (g *GasMeter) Limit() uint64 {
+	return g.limit
+}
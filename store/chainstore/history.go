@@ -0,0 +1,257 @@
+package chainstore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/sonata-labs/sonata/store/chainstore/smt"
+)
+
+// State history is bookkeeping for reads at past heights and is not part of
+// the committed state itself.
+//
+// Every state key written at a height records the value it held before, so
+// the value at height h is the one recorded by the first write after h, or
+// the current value if the key has not been written since. Pruning up to a
+// height drops the records only needed to read earlier heights, along with
+// the tree nodes replaced at those heights.
+const (
+	HistoryValuePrefix = "history/value/"
+	HistoryIndexPrefix = "history/index/"
+	HistoryEarliestKey = "history/earliest"
+	TreeOrphanPrefix   = "tree/orphan/"
+)
+
+// historyKeyPrefix returns the prefix of the history records of a state key.
+// The key is length prefixed so one key's records never interleave with
+// another's.
+func historyKeyPrefix(key []byte) []byte {
+	prefix := []byte(HistoryValuePrefix)
+	prefix = binary.BigEndian.AppendUint32(prefix, uint32(len(key)))
+	return append(prefix, key...)
+}
+
+// historyKey returns the key of the record of the value key held before it
+// was written at version.
+func historyKey(key []byte, version uint64) []byte {
+	return binary.BigEndian.AppendUint64(historyKeyPrefix(key), version)
+}
+
+// historyIndexKey lists key under the version it was written at, so pruning
+// finds the records of a version without scanning every key.
+func historyIndexKey(version uint64, key []byte) []byte {
+	index := binary.BigEndian.AppendUint64([]byte(HistoryIndexPrefix), version)
+	return append(index, key...)
+}
+
+func versionPrefix(prefix string, version uint64) []byte {
+	return binary.BigEndian.AppendUint64([]byte(prefix), version)
+}
+
+func treeOrphanKey(version uint64, ref smt.Ref) []byte {
+	return append(versionPrefix(TreeOrphanPrefix, version), ref.Bytes()...)
+}
+
+// History records hold the previous value behind a marker byte, so an absent
+// key can be told apart from an empty value.
+const (
+	historyAbsent  byte = 0
+	historyPresent byte = 1
+)
+
+// recordHistory records the committed value of every key written in this
+// batch before the writes at version, and the tree nodes the writes replaced.
+func (c *ChainStore) recordHistory(version uint64, keys []string, orphans []smt.Ref) error {
+	// a store written before history was kept can only be read back to the
+	// height before its first recorded writes
+	if _, closer, err := c.reader.Get([]byte(HistoryEarliestKey)); errors.Is(err, pebble.ErrNotFound) {
+		earliest := version
+		if earliest > 0 {
+			earliest--
+		}
+		if err := c.writer.Set([]byte(HistoryEarliestKey), binary.BigEndian.AppendUint64(nil, earliest), nil); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	} else {
+		closer.Close()
+	}
+
+	for _, key := range keys {
+		// the database still holds the state of the previous height, the
+		// batch holds this height's writes
+		record := []byte{historyAbsent}
+		value, closer, err := c.db.Get([]byte(key))
+		switch {
+		case errors.Is(err, pebble.ErrNotFound):
+		case err != nil:
+			return err
+		default:
+			record = append([]byte{historyPresent}, value...)
+			closer.Close()
+		}
+
+		if err := c.writer.Set(historyKey([]byte(key), version), record, nil); err != nil {
+			return err
+		}
+		if err := c.writer.Set(historyIndexKey(version, []byte(key)), nil, nil); err != nil {
+			return err
+		}
+	}
+	for _, ref := range orphans {
+		if err := c.writer.Set(treeOrphanKey(version, ref), nil, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EarliestHeight returns the earliest height whose state can still be read.
+func (c *ChainStore) EarliestHeight() (int64, error) {
+	data, closer, err := c.reader.Get([]byte(HistoryEarliestKey))
+	if errors.Is(err, pebble.ErrNotFound) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	defer closer.Close()
+	if len(data) != 8 {
+		return 0, fmt.Errorf("malformed earliest height")
+	}
+	return int64(binary.BigEndian.Uint64(data)), nil
+}
+
+// AtHeight returns a read only view of the state committed at height. The
+// view reads a snapshot of the database and must be closed.
+func (c *ChainStore) AtHeight(height int64) (*ChainStore, error) {
+	snapshot := c.db.NewSnapshot()
+	view := &ChainStore{db: c.db, reader: snapshot, snapshot: snapshot, historical: true, version: uint64(height)}
+
+	latest, _, err := view.GetLastBlock()
+	if err != nil {
+		snapshot.Close()
+		return nil, err
+	}
+	earliest, err := view.EarliestHeight()
+	if err != nil {
+		snapshot.Close()
+		return nil, err
+	}
+	if height < earliest || height > latest {
+		snapshot.Close()
+		return nil, fmt.Errorf("state at height %d is not available, heights %d to %d are", height, earliest, latest)
+	}
+	return view, nil
+}
+
+// readAt reads key as it was at the view's height.
+func (c *ChainStore) readAt(key []byte) ([]byte, error) {
+	iter, err := c.reader.NewIter(&pebble.IterOptions{
+		LowerBound: historyKey(key, c.version+1),
+		UpperBound: prefixEnd(historyKeyPrefix(key)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	// the first write after the height recorded the value it replaced
+	if iter.First() {
+		record, err := iter.ValueAndErr()
+		if err != nil {
+			return nil, err
+		}
+		if len(record) == 0 || record[0] == historyAbsent {
+			return nil, pebble.ErrNotFound
+		}
+		return bytes.Clone(record[1:]), nil
+	}
+	if err := iter.Error(); err != nil {
+		return nil, err
+	}
+
+	// not written since, so the current value is the one at the height
+	value, closer, err := c.reader.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+	return bytes.Clone(value), nil
+}
+
+// PruneHistory drops the history and tree nodes only needed to read heights
+// before height, which becomes the earliest readable height. The deletes are
+// written to the batch.
+func (c *ChainStore) PruneHistory(height int64) error {
+	if c.batch == nil {
+		return fmt.Errorf("batch not started")
+	}
+	earliest, err := c.EarliestHeight()
+	if err != nil {
+		return err
+	}
+	if height <= earliest {
+		return nil
+	}
+	version := uint64(height)
+
+	// records written at a version hold values from before it
+	err = c.deleteRange([]byte(HistoryIndexPrefix), versionPrefix(HistoryIndexPrefix, version+1), func(indexKey []byte) error {
+		written := binary.BigEndian.Uint64(indexKey[len(HistoryIndexPrefix):])
+		key := indexKey[len(HistoryIndexPrefix)+8:]
+		return c.writer.Delete(historyKey(key, written), nil)
+	})
+	if err != nil {
+		return fmt.Errorf("pruning state history: %w", err)
+	}
+
+	// nodes replaced at a version are only part of earlier roots
+	err = c.deleteRange([]byte(TreeOrphanPrefix), versionPrefix(TreeOrphanPrefix, version+1), func(orphanKey []byte) error {
+		ref, err := smt.DecodeRef(orphanKey[len(TreeOrphanPrefix)+8:])
+		if err != nil {
+			return err
+		}
+		return c.writer.Delete(treeNodeKey(ref), nil)
+	})
+	if err != nil {
+		return fmt.Errorf("pruning state tree: %w", err)
+	}
+
+	if err := c.deleteRange([]byte(TreeRootPrefix), treeRootKey(version), nil); err != nil {
+		return fmt.Errorf("pruning state tree roots: %w", err)
+	}
+
+	return c.writer.Set([]byte(HistoryEarliestKey), binary.BigEndian.AppendUint64(nil, version), nil)
+}
+
+// deleteRange deletes every key in [start, end), calling fn with each key
+// first.
+func (c *ChainStore) deleteRange(start, end []byte, fn func(key []byte) error) error {
+	iter, err := c.reader.NewIter(&pebble.IterOptions{LowerBound: start, UpperBound: end})
+	if err != nil {
+		return err
+	}
+	var keys [][]byte
+	for iter.First(); iter.Valid(); iter.Next() {
+		keys = append(keys, bytes.Clone(iter.Key()))
+	}
+	if err := iter.Close(); err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if fn != nil {
+			if err := fn(key); err != nil {
+				return err
+			}
+		}
+		if err := c.writer.Delete(key, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
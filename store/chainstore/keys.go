@@ -5,6 +5,7 @@ const (
 	AccountPrefix = "account/"
 )
 
-func accountKey(address string) []byte {
-	return append([]byte(AccountPrefix), []byte(address)...)
+// AccountKey returns the state key of the account at address.
+func AccountKey(address string) []byte {
+	return Accounts.Key(address)
 }
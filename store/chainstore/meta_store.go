@@ -0,0 +1,54 @@
+package chainstore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/cockroachdb/pebble"
+)
+
+// Metadata about the last committed block. Written alongside the block's
+// state so a restarted node reports exactly what it has applied.
+const (
+	LastBlockHeightKey  = "meta/last_block_height"
+	LastBlockAppHashKey = "meta/last_block_app_hash"
+)
+
+// StoreLastBlock records the height and app hash of the block being
+// committed. It is written to the batch and is not part of the app hash.
+func (c *ChainStore) StoreLastBlock(height int64, appHash []byte) error {
+	if c.batch == nil {
+		return fmt.Errorf("batch not started")
+	}
+	if err := c.writer.Set([]byte(LastBlockHeightKey), binary.BigEndian.AppendUint64(nil, uint64(height)), nil); err != nil {
+		return err
+	}
+	return c.writer.Set([]byte(LastBlockAppHashKey), appHash, nil)
+}
+
+// GetLastBlock returns the height and app hash of the last committed block.
+// A store that has not committed any block returns height 0 and no hash.
+func (c *ChainStore) GetLastBlock() (int64, []byte, error) {
+	heightBytes, closer, err := c.reader.Get([]byte(LastBlockHeightKey))
+	if errors.Is(err, pebble.ErrNotFound) {
+		return 0, nil, nil
+	} else if err != nil {
+		return 0, nil, err
+	}
+	if len(heightBytes) != 8 {
+		closer.Close()
+		return 0, nil, fmt.Errorf("malformed last block height")
+	}
+	height := int64(binary.BigEndian.Uint64(heightBytes))
+	closer.Close()
+
+	appHash, closer, err := c.reader.Get([]byte(LastBlockAppHashKey))
+	if err != nil {
+		return 0, nil, fmt.Errorf("last block app hash: %w", err)
+	}
+	defer closer.Close()
+
+	return height, bytes.Clone(appHash), nil
+}
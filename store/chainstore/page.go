@@ -0,0 +1,70 @@
+package chainstore
+
+import (
+	"bytes"
+)
+
+const (
+	// DefaultPageLimit is the number of entries in a page when a listing does
+	// not ask for a number.
+	DefaultPageLimit = 100
+
+	// MaxPageLimit is the most entries a page can hold.
+	MaxPageLimit = 1000
+)
+
+// PageLimit returns the number of entries a page asked to hold limit entries
+// holds.
+func PageLimit(limit int) int {
+	switch {
+	case limit <= 0:
+		return DefaultPageLimit
+	case limit > MaxPageLimit:
+		return MaxPageLimit
+	default:
+		return limit
+	}
+}
+
+// IteratePage calls fn with the keys and values under prefix in key order,
+// starting at cursor, until fn has accepted a page of them. fn reports
+// whether it accepted an entry, so a listing can skip entries without them
+// counting towards the page. An empty cursor starts at the first key.
+//
+// IteratePage returns the cursor of the next page, or nil after the last
+// page. Cursors are opaque to callers, who pass them back unchanged. Like
+// iterate, it cannot be used on a branch or a view of a past height, and the
+// slices passed to fn are only valid until it returns.
+func (c *ChainStore) IteratePage(prefix, cursor []byte, limit int, fn func(key, value []byte) (bool, error)) ([]byte, error) {
+	limit = PageLimit(limit)
+
+	// a cursor is the remainder of the first key of the page after prefix,
+	// so it can never point outside the listing
+	start := append(bytes.Clone(prefix), cursor...)
+	iter, err := c.newIter(prefix, start)
+	if err != nil {
+		return nil, err
+	}
+
+	var accepted int
+	for iter.First(); iter.Valid(); iter.Next() {
+		if accepted == limit {
+			next := bytes.Clone(iter.Key()[len(prefix):])
+			return next, iter.Close()
+		}
+		value, err := iter.ValueAndErr()
+		if err != nil {
+			iter.Close()
+			return nil, err
+		}
+		ok, err := fn(iter.Key(), value)
+		if err != nil {
+			iter.Close()
+			return nil, err
+		}
+		if ok {
+			accepted++
+		}
+	}
+	return nil, iter.Close()
+}
@@ -0,0 +1,47 @@
+package chainstore
+
+import (
+	"errors"
+
+	"github.com/cockroachdb/pebble"
+	chainv1 "github.com/sonata-labs/sonata/gen/chain/v1"
+)
+
+const (
+	ParamsPrefix = "params/"
+	ParamsKey    = ParamsPrefix + paramsChain
+)
+
+// paramsChain is the key of the chain parameters in chainParams.
+const paramsChain = "chain"
+
+// chainParams holds the chain parameters under paramsChain.
+var chainParams = NewCollection(ParamsPrefix, StringKey, func() *chainv1.Params {
+	return &chainv1.Params{}
+})
+
+// DefaultParams are the chain parameters of a genesis without params, and of
+// chains started before params were kept in state.
+func DefaultParams() *chainv1.Params {
+	return &chainv1.Params{
+		MaxBlockGas: 20_000_000,
+		MinGasPrice: 1,
+		MaxTxBytes:  1 << 20,
+	}
+}
+
+// StoreParams stores the chain parameters.
+func (c *ChainStore) StoreParams(params *chainv1.Params) error {
+	return chainParams.Set(c, paramsChain, params)
+}
+
+// GetParams returns the chain parameters, or DefaultParams if none are stored.
+func (c *ChainStore) GetParams() (*chainv1.Params, error) {
+	params, err := chainParams.Get(c, paramsChain)
+	if errors.Is(err, pebble.ErrNotFound) {
+		return DefaultParams(), nil
+	} else if err != nil {
+		return nil, err
+	}
+	return params, nil
+}
@@ -0,0 +1,61 @@
+package chainstore
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cockroachdb/pebble"
+	cmtcrypto "github.com/cometbft/cometbft/api/cometbft/crypto/v1"
+	"github.com/sonata-labs/sonata/store/chainstore/smt"
+)
+
+// ProofOpType identifies state tree proofs in ABCI query responses.
+const ProofOpType = "sonata/smt"
+
+// GetWithProof reads a state key from the latest committed state, or the
+// height of a view returned by AtHeight, along with a proof against the app
+// hash of that state. A missing key returns a nil
+// value and a proof of its absence.
+func (c *ChainStore) GetWithProof(key []byte) ([]byte, *cmtcrypto.ProofOp, error) {
+	value, err := c.get(key)
+	if errors.Is(err, pebble.ErrNotFound) {
+		value = nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	root, err := c.stateRoot()
+	if err != nil {
+		return nil, nil, err
+	}
+	tree := smt.NewTree(&nodeStore{reader: c.reader, writer: c.writer})
+	proof, err := tree.Prove(root, smt.Path(key))
+	if err != nil {
+		return nil, nil, fmt.Errorf("proving %q: %w", key, err)
+	}
+
+	return value, &cmtcrypto.ProofOp{Type: ProofOpType, Key: key, Data: proof.Bytes()}, nil
+}
+
+// VerifyProofOp checks a proof returned by GetWithProof against an app hash.
+// A nil value verifies that the key is absent.
+func VerifyProofOp(op *cmtcrypto.ProofOp, appHash []byte, value []byte) error {
+	if op.Type != ProofOpType {
+		return fmt.Errorf("unexpected proof type %q", op.Type)
+	}
+	if len(appHash) != smt.HashSize {
+		return fmt.Errorf("invalid app hash length %d", len(appHash))
+	}
+	proof, err := smt.DecodeProof(op.Data)
+	if err != nil {
+		return err
+	}
+
+	var root smt.Hash
+	copy(root[:], appHash)
+	if value == nil {
+		return proof.Verify(root, smt.Path(op.Key), nil)
+	}
+	valueHash := smt.ValueHash(value)
+	return proof.Verify(root, smt.Path(op.Key), &valueHash)
+}
@@ -0,0 +1,186 @@
+package chainstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/cockroachdb/pebble"
+)
+
+// SchemaVersionKey records the version of the key layout the state is
+// written in. It is part of the state, so every node agrees on it and state
+// sync carries it. State without the key is at version 1, the layout from
+// before versions were recorded, and has the key written by the next upgrade.
+const SchemaVersionKey = "schema/version"
+
+// BaselineCheckedKey records that the store was found in the version 1 layout
+// while it had no schema version. Blocks run before the next upgrade write
+// keys outside that layout, so the check is only made the first time the
+// store is opened. It is not part of the state.
+const BaselineCheckedKey = "meta/schema_baseline_checked"
+
+// ErrSchemaTooNew is returned for a store written by a newer binary, whose
+// key layout this binary cannot read.
+var ErrSchemaTooNew = errors.New("schema version newer than this binary")
+
+// Migration moves the state from the schema version before Version to
+// Version. It runs at the height of the Upgrade plan it belongs to, before the
+// block's transactions, and its writes are committed with the block.
+type Migration struct {
+	Version uint64
+	Upgrade string
+	Migrate func(ctx context.Context, store *ChainStore) error
+}
+
+// migrations are the chain store migrations, in version order. A release
+// changing the key layout appends one, along with the upgrade it runs with.
+var migrations = []Migration{
+	{Version: 2, Upgrade: UploaderIndexUpgrade, Migrate: indexUploaders},
+}
+
+// LatestSchemaVersion returns the schema version this binary writes.
+func LatestSchemaVersion() uint64 {
+	if len(migrations) == 0 {
+		return 1
+	}
+	return migrations[len(migrations)-1].Version
+}
+
+func checkMigrations(ms []Migration) error {
+	for i, m := range ms {
+		if m.Version != uint64(i)+2 {
+			return fmt.Errorf("migration %d to schema version %d is out of order", i, m.Version)
+		}
+		if m.Upgrade == "" || m.Migrate == nil {
+			return fmt.Errorf("migration to schema version %d has no upgrade or function", m.Version)
+		}
+	}
+	return nil
+}
+
+// SchemaVersion returns the schema version of the state c reads.
+func (c *ChainStore) SchemaVersion() (uint64, error) {
+	data, err := c.read([]byte(SchemaVersionKey))
+	if errors.Is(err, pebble.ErrNotFound) {
+		return 1, nil
+	} else if err != nil {
+		return 0, err
+	}
+	return decodeSchemaVersion(data)
+}
+
+func decodeSchemaVersion(data []byte) (uint64, error) {
+	if len(data) != 8 {
+		return 0, fmt.Errorf("malformed schema version")
+	}
+	return binary.BigEndian.Uint64(data), nil
+}
+
+func (c *ChainStore) setSchemaVersion(version uint64) error {
+	return c.set([]byte(SchemaVersionKey), binary.BigEndian.AppendUint64(nil, version))
+}
+
+// CheckSchema returns ErrSchemaTooNew if the committed state was written by a
+// newer binary, and an error if state without a version is not in the
+// version 1 layout.
+func (c *ChainStore) CheckSchema() error {
+	if err := checkMigrations(migrations); err != nil {
+		return err
+	}
+	if _, err := c.read([]byte(SchemaVersionKey)); errors.Is(err, pebble.ErrNotFound) {
+		if _, err := c.read([]byte(BaselineCheckedKey)); err == nil || !errors.Is(err, pebble.ErrNotFound) {
+			return err
+		}
+		if err := c.checkBaseline(); err != nil {
+			return err
+		}
+		return c.db.Set([]byte(BaselineCheckedKey), []byte{1}, pebble.Sync)
+	} else if err != nil {
+		return err
+	}
+	version, err := c.SchemaVersion()
+	if err != nil {
+		return err
+	}
+	if version > LatestSchemaVersion() {
+		return fmt.Errorf("%w: chain store is at version %d, binary at %d", ErrSchemaTooNew, version, LatestSchemaVersion())
+	}
+	return nil
+}
+
+// baselinePrefixes are the key prefixes of the version 1 layout, written
+// before versions were recorded, along with the bookkeeping kept beside it.
+var baselinePrefixes = append([]string{
+	AccountPrefix, UploadPrefix, UploadOriginalPrefix,
+}, bookkeepingPrefixes...)
+
+// checkBaseline returns an error if the store holds a key outside the version
+// 1 layout. It seeks past each prefix it finds rather than reading every key.
+func (c *ChainStore) checkBaseline() error {
+	iter, err := c.newIter(nil, nil)
+	if err != nil {
+		return err
+	}
+	for valid := iter.First(); valid; {
+		var prefix string
+		for _, p := range baselinePrefixes {
+			if bytes.HasPrefix(iter.Key(), []byte(p)) {
+				prefix = p
+				break
+			}
+		}
+		if prefix == "" {
+			key := string(iter.Key())
+			iter.Close()
+			return fmt.Errorf("chain store has no schema version but key %q is not in the version 1 layout", key)
+		}
+		valid = iter.SeekGE(prefixEnd([]byte(prefix)))
+	}
+	return iter.Close()
+}
+
+// InitSchema records the schema version of a new chain's genesis state.
+func (c *ChainStore) InitSchema() error {
+	return c.setSchemaVersion(LatestSchemaVersion())
+}
+
+// Migrate runs the migrations belonging to the named upgrade, in order, and
+// records the schema version each one reaches. Migrations must run in version
+// order, so one is never skipped.
+func (c *ChainStore) Migrate(ctx context.Context, upgrade string) error {
+	// state from before versions were recorded is at version 1, which
+	// CheckSchema verified when the store was opened
+	if _, err := c.read([]byte(SchemaVersionKey)); errors.Is(err, pebble.ErrNotFound) {
+		if err := c.setSchemaVersion(1); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if m.Upgrade != upgrade {
+			continue
+		}
+		version, err := c.SchemaVersion()
+		if err != nil {
+			return err
+		}
+		if version >= m.Version {
+			continue
+		}
+		if version != m.Version-1 {
+			return fmt.Errorf("state at schema version %d cannot be migrated to %d", version, m.Version)
+		}
+		if err := m.Migrate(ctx, c); err != nil {
+			return fmt.Errorf("migrating to schema version %d: %w", m.Version, err)
+		}
+		if err := c.setSchemaVersion(m.Version); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package chainstore
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestMigrateIndexesUploaders(t *testing.T) {
+	store := newTestBatch(t)
+	for _, u := range []op{upload("t1", "o1", "alice"), upload("t2", "o2", "bob"), upload("t3", "o3", "alice"), upload("t4", "o4", "")} {
+		if err := Uploads.Set(store, u.TranscodedCid, u); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// state from before the index has only some of its entries, and one
+	// left over from an upload that is gone
+	for _, key := range [][]byte{UploadsByUploader.Key("alice", "t1"), UploadsByUploader.Key("bob", "t2")} {
+		if err := store.remove(key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.set(UploadsByUploader.Key("carol", "t9"), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.Migrate(context.Background(), "other-upgrade"); err != nil {
+		t.Fatal(err)
+	}
+	if version, err := store.SchemaVersion(); err != nil || version != 1 {
+		t.Fatalf("schema version %d (%v) after another upgrade, want 1", version, err)
+	}
+	if err := store.Migrate(context.Background(), UploaderIndexUpgrade); err != nil {
+		t.Fatal(err)
+	}
+	if version, err := store.SchemaVersion(); err != nil || version != 2 {
+		t.Fatalf("schema version %d (%v), want 2", version, err)
+	}
+
+	for uploader, want := range map[string][]string{"alice": {"t1", "t3"}, "bob": {"t2"}, "carol": nil} {
+		var got []string
+		if err := UploadsByUploader.Iterate(store, uploader, func(cid string) error {
+			got = append(got, cid)
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("uploader %s indexes %v, want %v", uploader, got, want)
+		}
+	}
+}
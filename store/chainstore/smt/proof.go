@@ -0,0 +1,148 @@
+package smt
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidProof is returned when a proof does not match the root it is
+// verified against.
+var ErrInvalidProof = errors.New("smt: invalid proof")
+
+// Proof shows that a path holds a value hash under a root, or that it holds
+// nothing.
+type Proof struct {
+	// Siblings are the hashes of the siblings along the path, from the root
+	// down to the node the path ends at.
+	Siblings []Hash
+
+	// Leaf is the leaf the path ends at, or nil if it ends at an empty
+	// subtree. A leaf for a different path proves the path is absent.
+	Leaf *ProofLeaf
+}
+
+// ProofLeaf is the leaf a proof ends at.
+type ProofLeaf struct {
+	Path      Hash
+	ValueHash Hash
+}
+
+// Prove returns a proof for path under root.
+func (t *Tree) Prove(root Ref, path Hash) (*Proof, error) {
+	proof := &Proof{}
+	ref := root
+	for depth := 0; ; depth++ {
+		n, err := t.load(ref)
+		if err != nil {
+			return nil, err
+		}
+		if n == nil {
+			return proof, nil
+		}
+		if n.leaf {
+			proof.Leaf = &ProofLeaf{Path: n.path, ValueHash: n.valueHash}
+			return proof, nil
+		}
+		if bit(path, depth) == 0 {
+			proof.Siblings = append(proof.Siblings, n.right.Hash)
+			ref = n.left
+		} else {
+			proof.Siblings = append(proof.Siblings, n.left.Hash)
+			ref = n.right
+		}
+	}
+}
+
+// Root returns the root hash the proof commits to for path.
+func (p *Proof) Root(path Hash) (Hash, error) {
+	if len(p.Siblings) > 8*HashSize {
+		return Hash{}, fmt.Errorf("%w: %d siblings", ErrInvalidProof, len(p.Siblings))
+	}
+
+	var h Hash
+	if p.Leaf != nil {
+		h = leafHash(p.Leaf.Path, p.Leaf.ValueHash)
+	}
+	for depth := len(p.Siblings) - 1; depth >= 0; depth-- {
+		if bit(path, depth) == 0 {
+			h = innerHash(h, p.Siblings[depth])
+		} else {
+			h = innerHash(p.Siblings[depth], h)
+		}
+	}
+	return h, nil
+}
+
+// Verify checks the proof against root. With a nil valueHash it checks that
+// path is absent, otherwise that path holds valueHash.
+func (p *Proof) Verify(root Hash, path Hash, valueHash *Hash) error {
+	computed, err := p.Root(path)
+	if err != nil {
+		return err
+	}
+	if computed != root {
+		return fmt.Errorf("%w: root mismatch", ErrInvalidProof)
+	}
+
+	exists := p.Leaf != nil && p.Leaf.Path == path
+	switch {
+	case valueHash == nil && exists:
+		return fmt.Errorf("%w: path is present", ErrInvalidProof)
+	case valueHash != nil && !exists:
+		return fmt.Errorf("%w: path is absent", ErrInvalidProof)
+	case valueHash != nil && p.Leaf.ValueHash != *valueHash:
+		return fmt.Errorf("%w: value mismatch", ErrInvalidProof)
+	}
+	return nil
+}
+
+// Bytes encodes the proof as a leaf flag, the leaf if present and the
+// sibling hashes.
+func (p *Proof) Bytes() []byte {
+	b := make([]byte, 0, 1+2*HashSize+len(p.Siblings)*HashSize)
+	if p.Leaf != nil {
+		b = append(b, 1)
+		b = append(b, p.Leaf.Path[:]...)
+		b = append(b, p.Leaf.ValueHash[:]...)
+	} else {
+		b = append(b, 0)
+	}
+	for _, sibling := range p.Siblings {
+		b = append(b, sibling[:]...)
+	}
+	return b
+}
+
+// DecodeProof decodes a proof produced by Proof.Bytes.
+func DecodeProof(b []byte) (*Proof, error) {
+	if len(b) == 0 {
+		return nil, fmt.Errorf("%w: empty encoding", ErrInvalidProof)
+	}
+
+	proof := &Proof{}
+	flag, rest := b[0], b[1:]
+	switch flag {
+	case 0:
+	case 1:
+		if len(rest) < 2*HashSize {
+			return nil, fmt.Errorf("%w: truncated leaf", ErrInvalidProof)
+		}
+		proof.Leaf = &ProofLeaf{}
+		copy(proof.Leaf.Path[:], rest)
+		copy(proof.Leaf.ValueHash[:], rest[HashSize:])
+		rest = rest[2*HashSize:]
+	default:
+		return nil, fmt.Errorf("%w: unknown leaf flag %d", ErrInvalidProof, flag)
+	}
+
+	if len(rest)%HashSize != 0 {
+		return nil, fmt.Errorf("%w: truncated siblings", ErrInvalidProof)
+	}
+	for len(rest) > 0 {
+		var sibling Hash
+		copy(sibling[:], rest)
+		proof.Siblings = append(proof.Siblings, sibling)
+		rest = rest[HashSize:]
+	}
+	return proof, nil
+}
@@ -0,0 +1,370 @@
+// Package smt implements a versioned, compacted sparse Merkle tree.
+//
+// Keys are mapped to 256 bit paths and values are committed to by their
+// hash. A subtree holding a single leaf is collapsed into that leaf, so the
+// depth of the tree grows with log2 of the number of leaves rather than the
+// width of the path. The shape of the tree only depends on its contents, not
+// on the order the contents were written in, which makes the root hash
+// deterministic across nodes that apply the same writes.
+//
+// Nodes are immutable and addressed by the version that created them and
+// their hash, so the roots of older versions stay readable until pruned.
+package smt
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+const (
+	// HashSize is the size of node hashes, paths and value hashes.
+	HashSize = sha256.Size
+
+	// RefSize is the size of an encoded node reference.
+	RefSize = 8 + HashSize
+
+	leafPrefix  byte = 0x00
+	innerPrefix byte = 0x01
+
+	leafSize  = 1 + 2*HashSize
+	innerSize = 1 + 2*RefSize
+)
+
+// ErrNodeNotFound is returned by a NodeStore when a node does not exist.
+var ErrNodeNotFound = errors.New("smt: node not found")
+
+// Hash is a node hash, a key path or a value hash.
+type Hash [HashSize]byte
+
+// IsZero reports whether h is the empty hash.
+func (h Hash) IsZero() bool {
+	return h == Hash{}
+}
+
+// Path returns the tree path for a store key.
+func Path(key []byte) Hash {
+	return sha256.Sum256(key)
+}
+
+// ValueHash returns the hash a leaf commits to for a value.
+func ValueHash(value []byte) Hash {
+	return sha256.Sum256(value)
+}
+
+// Ref points at a stored node. The zero Ref is the empty subtree.
+type Ref struct {
+	Version uint64
+	Hash    Hash
+}
+
+// IsEmpty reports whether r is the empty subtree.
+func (r Ref) IsEmpty() bool {
+	return r.Hash.IsZero()
+}
+
+// Bytes encodes the reference as version (big endian) followed by hash.
+func (r Ref) Bytes() []byte {
+	b := make([]byte, RefSize)
+	binary.BigEndian.PutUint64(b, r.Version)
+	copy(b[8:], r.Hash[:])
+	return b
+}
+
+// DecodeRef decodes a reference produced by Ref.Bytes.
+func DecodeRef(b []byte) (Ref, error) {
+	if len(b) != RefSize {
+		return Ref{}, fmt.Errorf("smt: invalid ref length %d", len(b))
+	}
+	var r Ref
+	r.Version = binary.BigEndian.Uint64(b)
+	copy(r.Hash[:], b[8:])
+	return r, nil
+}
+
+// NodeStore persists encoded tree nodes.
+type NodeStore interface {
+	GetNode(ref Ref) ([]byte, error)
+	SetNode(ref Ref, node []byte) error
+}
+
+// Entry is a single write applied to the tree.
+type Entry struct {
+	Path      Hash
+	ValueHash Hash
+	Delete    bool
+}
+
+type node struct {
+	leaf bool
+
+	// leaf fields
+	path      Hash
+	valueHash Hash
+
+	// inner fields
+	left  Ref
+	right Ref
+}
+
+func leafHash(path, valueHash Hash) Hash {
+	return sha256.Sum256(encodeLeaf(path, valueHash))
+}
+
+func innerHash(left, right Hash) Hash {
+	var buf [1 + 2*HashSize]byte
+	buf[0] = innerPrefix
+	copy(buf[1:], left[:])
+	copy(buf[1+HashSize:], right[:])
+	return sha256.Sum256(buf[:])
+}
+
+func encodeLeaf(path, valueHash Hash) []byte {
+	b := make([]byte, leafSize)
+	b[0] = leafPrefix
+	copy(b[1:], path[:])
+	copy(b[1+HashSize:], valueHash[:])
+	return b
+}
+
+func encodeInner(left, right Ref) []byte {
+	b := make([]byte, innerSize)
+	b[0] = innerPrefix
+	copy(b[1:], left.Bytes())
+	copy(b[1+RefSize:], right.Bytes())
+	return b
+}
+
+func decodeNode(b []byte) (*node, error) {
+	switch {
+	case len(b) == leafSize && b[0] == leafPrefix:
+		n := &node{leaf: true}
+		copy(n.path[:], b[1:])
+		copy(n.valueHash[:], b[1+HashSize:])
+		return n, nil
+	case len(b) == innerSize && b[0] == innerPrefix:
+		left, err := DecodeRef(b[1 : 1+RefSize])
+		if err != nil {
+			return nil, err
+		}
+		right, err := DecodeRef(b[1+RefSize:])
+		if err != nil {
+			return nil, err
+		}
+		return &node{left: left, right: right}, nil
+	default:
+		return nil, fmt.Errorf("smt: malformed node of %d bytes", len(b))
+	}
+}
+
+// bit returns the bit of h at depth i, counting from the most significant bit.
+func bit(h Hash, i int) int {
+	return int(h[i/8]>>(7-uint(i%8))) & 1
+}
+
+// Tree reads and writes nodes through a NodeStore.
+type Tree struct {
+	store NodeStore
+
+	// nodes of the previous root replaced by the last Update
+	orphans []Ref
+}
+
+func NewTree(store NodeStore) *Tree {
+	return &Tree{store: store}
+}
+
+func (t *Tree) load(ref Ref) (*node, error) {
+	if ref.IsEmpty() {
+		return nil, nil
+	}
+	b, err := t.store.GetNode(ref)
+	if err != nil {
+		return nil, fmt.Errorf("smt: loading node %d/%x: %w", ref.Version, ref.Hash[:4], err)
+	}
+	return decodeNode(b)
+}
+
+// Update applies entries on top of root and returns the new root. New nodes
+// are written at the given version. Entries may be in any order but must not
+// contain the same path twice.
+func (t *Tree) Update(root Ref, version uint64, entries []Entry) (Ref, error) {
+	t.orphans = nil
+	if len(entries) == 0 {
+		return root, nil
+	}
+
+	sorted := make([]pending, len(entries))
+	for i, e := range entries {
+		sorted[i] = pending{Entry: e}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i].Path[:], sorted[j].Path[:]) < 0
+	})
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].Path == sorted[i-1].Path {
+			return Ref{}, fmt.Errorf("smt: duplicate path %x", sorted[i].Path)
+		}
+	}
+
+	return t.update(root, version, 0, sorted)
+}
+
+// Orphans returns the nodes of the root passed to the last Update that are
+// not part of the root it returned. They are only needed to read versions
+// before the update and can be deleted once those are pruned.
+func (t *Tree) Orphans() []Ref {
+	return t.orphans
+}
+
+// pending is an entry being applied. Leaves that already exist in the tree
+// and are only moved carry their original reference so they are not
+// rewritten.
+type pending struct {
+	Entry
+	existing *Ref
+}
+
+func (t *Tree) update(ref Ref, version uint64, depth int, entries []pending) (Ref, error) {
+	if len(entries) == 0 {
+		return ref, nil
+	}
+
+	n, err := t.load(ref)
+	if err != nil {
+		return Ref{}, err
+	}
+
+	if n != nil && !n.leaf {
+		// an inner node with changes below it is always rebuilt
+		t.orphans = append(t.orphans, ref)
+		split := splitIndex(entries, depth)
+		left, err := t.update(n.left, version, depth+1, entries[:split])
+		if err != nil {
+			return Ref{}, err
+		}
+		right, err := t.update(n.right, version, depth+1, entries[split:])
+		if err != nil {
+			return Ref{}, err
+		}
+		return t.join(left, right, version)
+	}
+
+	if n != nil {
+		// fold the existing leaf into the entries unless it is being overwritten
+		i := sort.Search(len(entries), func(i int) bool {
+			return bytes.Compare(entries[i].Path[:], n.path[:]) >= 0
+		})
+		if i == len(entries) || entries[i].Path != n.path {
+			existing := ref
+			folded := pending{Entry: Entry{Path: n.path, ValueHash: n.valueHash}, existing: &existing}
+			entries = append(entries[:i:i], append([]pending{folded}, entries[i:]...)...)
+		} else {
+			t.orphans = append(t.orphans, ref)
+		}
+	}
+
+	return t.build(version, depth, entries)
+}
+
+// build creates the subtree at depth holding the non-deleted entries.
+func (t *Tree) build(version uint64, depth int, entries []pending) (Ref, error) {
+	live := entries[:0:0]
+	for _, e := range entries {
+		if !e.Delete {
+			live = append(live, e)
+		}
+	}
+
+	switch len(live) {
+	case 0:
+		return Ref{}, nil
+	case 1:
+		if live[0].existing != nil {
+			return *live[0].existing, nil
+		}
+		return t.putLeaf(version, live[0].Path, live[0].ValueHash)
+	}
+
+	split := splitIndex(live, depth)
+	left, err := t.build(version, depth+1, live[:split])
+	if err != nil {
+		return Ref{}, err
+	}
+	right, err := t.build(version, depth+1, live[split:])
+	if err != nil {
+		return Ref{}, err
+	}
+	return t.join(left, right, version)
+}
+
+// join returns the node with the given children, collapsing it into its
+// only child when that child is a leaf.
+func (t *Tree) join(left, right Ref, version uint64) (Ref, error) {
+	switch {
+	case left.IsEmpty() && right.IsEmpty():
+		return Ref{}, nil
+	case left.IsEmpty() || right.IsEmpty():
+		only := left
+		if only.IsEmpty() {
+			only = right
+		}
+		n, err := t.load(only)
+		if err != nil {
+			return Ref{}, err
+		}
+		if n.leaf {
+			return only, nil
+		}
+	}
+
+	ref := Ref{Version: version, Hash: innerHash(left.Hash, right.Hash)}
+	if err := t.store.SetNode(ref, encodeInner(left, right)); err != nil {
+		return Ref{}, err
+	}
+	return ref, nil
+}
+
+func (t *Tree) putLeaf(version uint64, path, valueHash Hash) (Ref, error) {
+	ref := Ref{Version: version, Hash: leafHash(path, valueHash)}
+	if err := t.store.SetNode(ref, encodeLeaf(path, valueHash)); err != nil {
+		return Ref{}, err
+	}
+	return ref, nil
+}
+
+// splitIndex returns the index of the first entry whose path has a 1 bit at
+// depth. Entries must be sorted by path.
+func splitIndex(entries []pending, depth int) int {
+	return sort.Search(len(entries), func(i int) bool {
+		return bit(entries[i].Path, depth) == 1
+	})
+}
+
+// Get returns the value hash stored at path under root, and whether it exists.
+func (t *Tree) Get(root Ref, path Hash) (Hash, bool, error) {
+	ref := root
+	for depth := 0; ; depth++ {
+		n, err := t.load(ref)
+		if err != nil {
+			return Hash{}, false, err
+		}
+		if n == nil {
+			return Hash{}, false, nil
+		}
+		if n.leaf {
+			if n.path != path {
+				return Hash{}, false, nil
+			}
+			return n.valueHash, true, nil
+		}
+		if bit(path, depth) == 0 {
+			ref = n.left
+		} else {
+			ref = n.right
+		}
+	}
+}
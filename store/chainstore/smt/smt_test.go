@@ -0,0 +1,289 @@
+package smt
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// memStore is a NodeStore kept in memory.
+type memStore map[Ref][]byte
+
+func (m memStore) GetNode(ref Ref) ([]byte, error) {
+	b, ok := m[ref]
+	if !ok {
+		return nil, ErrNodeNotFound
+	}
+	return b, nil
+}
+
+func (m memStore) SetNode(ref Ref, node []byte) error {
+	m[ref] = node
+	return nil
+}
+
+func keys(n int) []string {
+	ks := make([]string, n)
+	for i := range ks {
+		ks[i] = fmt.Sprintf("key-%d", i)
+	}
+	return ks
+}
+
+func set(key, value string) Entry {
+	return Entry{Path: Path([]byte(key)), ValueHash: ValueHash([]byte(value))}
+}
+
+func del(key string) Entry {
+	return Entry{Path: Path([]byte(key)), Delete: true}
+}
+
+// apply writes each batch of entries as its own version and returns the
+// final root.
+func apply(t *testing.T, tree *Tree, batches ...[]Entry) Ref {
+	t.Helper()
+	var root Ref
+	for i, entries := range batches {
+		var err error
+		root, err = tree.Update(root, uint64(i+1), entries)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestRootIndependentOfOrder(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 3, 17, 200} {
+		t.Run(fmt.Sprintf("%d keys", n), func(t *testing.T) {
+			ks := keys(n)
+			var all []Entry
+			for _, k := range ks {
+				all = append(all, set(k, "v-"+k))
+			}
+			want := apply(t, NewTree(memStore{}), all)
+
+			one := func(order []string) [][]Entry {
+				batches := make([][]Entry, len(order))
+				for i, k := range order {
+					batches[i] = []Entry{set(k, "v-"+k)}
+				}
+				return batches
+			}
+			reversed := make([]string, n)
+			for i, k := range ks {
+				reversed[n-1-i] = k
+			}
+			shuffled := append([]string(nil), ks...)
+			rand.New(rand.NewSource(1)).Shuffle(n, func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+
+			// extra keys written and deleted again leave no trace
+			extra := keys(n + 10)[n:]
+			var withExtra, deleteExtra []Entry
+			for _, k := range extra {
+				withExtra = append(withExtra, set(k, "extra"))
+				deleteExtra = append(deleteExtra, del(k))
+			}
+
+			tests := []struct {
+				name    string
+				batches [][]Entry
+			}{
+				{"one at a time", one(ks)},
+				{"reversed", one(reversed)},
+				{"shuffled", one(shuffled)},
+				{"inserted then deleted", [][]Entry{withExtra, all, deleteExtra}},
+				{"deleted in the same update", [][]Entry{append(append([]Entry(nil), withExtra...), all...), deleteExtra}},
+			}
+			for _, tt := range tests {
+				if got := apply(t, NewTree(memStore{}), tt.batches...); got.Hash != want.Hash {
+					t.Errorf("%s: root %x, want %x", tt.name, got.Hash, want.Hash)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateRejectsDuplicatePaths(t *testing.T) {
+	_, err := NewTree(memStore{}).Update(Ref{}, 1, []Entry{set("a", "1"), set("a", "2")})
+	if err == nil {
+		t.Fatal("expected an error for a duplicate path")
+	}
+}
+
+func TestProofs(t *testing.T) {
+	tests := []struct {
+		name    string
+		present []string
+		absent  []string
+	}{
+		{"empty tree", nil, []string{"a"}},
+		{"single leaf", []string{"a"}, []string{"b"}},
+		{"many leaves", keys(50), []string{"missing", "key-50", "key-999"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree := NewTree(memStore{})
+			var entries []Entry
+			for _, k := range tt.present {
+				entries = append(entries, set(k, "v-"+k))
+			}
+			root := apply(t, tree, entries)
+
+			for _, k := range tt.present {
+				proof, err := tree.Prove(root, Path([]byte(k)))
+				if err != nil {
+					t.Fatal(err)
+				}
+				decoded, err := DecodeProof(proof.Bytes())
+				if err != nil {
+					t.Fatal(err)
+				}
+				value := ValueHash([]byte("v-" + k))
+				if err := decoded.Verify(root.Hash, Path([]byte(k)), &value); err != nil {
+					t.Errorf("membership of %s: %v", k, err)
+				}
+				if err := decoded.Verify(root.Hash, Path([]byte(k)), nil); !errors.Is(err, ErrInvalidProof) {
+					t.Errorf("non-membership of present %s: got %v, want ErrInvalidProof", k, err)
+				}
+				wrong := ValueHash([]byte("wrong"))
+				if err := decoded.Verify(root.Hash, Path([]byte(k)), &wrong); !errors.Is(err, ErrInvalidProof) {
+					t.Errorf("membership of %s with a wrong value: got %v, want ErrInvalidProof", k, err)
+				}
+			}
+
+			for _, k := range tt.absent {
+				proof, err := tree.Prove(root, Path([]byte(k)))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := proof.Verify(root.Hash, Path([]byte(k)), nil); err != nil {
+					t.Errorf("non-membership of %s: %v", k, err)
+				}
+				value := ValueHash([]byte("v-" + k))
+				if err := proof.Verify(root.Hash, Path([]byte(k)), &value); !errors.Is(err, ErrInvalidProof) {
+					t.Errorf("membership of absent %s: got %v, want ErrInvalidProof", k, err)
+				}
+				if err := proof.Verify(ValueHash([]byte("other root")), Path([]byte(k)), nil); !errors.Is(err, ErrInvalidProof) {
+					t.Errorf("non-membership of %s under another root: got %v, want ErrInvalidProof", k, err)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeProofRejectsMalformed(t *testing.T) {
+	tests := []struct {
+		name string
+		b    []byte
+	}{
+		{"empty", nil},
+		{"unknown flag", []byte{2}},
+		{"truncated leaf", append([]byte{1}, make([]byte, HashSize)...)},
+		{"truncated sibling", append([]byte{0}, make([]byte, HashSize-1)...)},
+	}
+	for _, tt := range tests {
+		if _, err := DecodeProof(tt.b); !errors.Is(err, ErrInvalidProof) {
+			t.Errorf("%s: got %v, want ErrInvalidProof", tt.name, err)
+		}
+	}
+}
+
+func TestOldVersionsReadable(t *testing.T) {
+	tree := NewTree(memStore{})
+	v1, err := tree.Update(Ref{}, 1, []Entry{set("a", "1"), set("b", "1"), set("c", "1")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	v2, err := tree.Update(v1, 2, []Entry{set("a", "2"), del("b"), set("d", "2")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		root  Ref
+		key   string
+		value string
+	}{
+		{v1, "a", "1"}, {v1, "b", "1"}, {v1, "c", "1"}, {v1, "d", ""},
+		{v2, "a", "2"}, {v2, "b", ""}, {v2, "c", "1"}, {v2, "d", "2"},
+	}
+	for _, tt := range tests {
+		got, ok, err := tree.Get(tt.root, Path([]byte(tt.key)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := tt.value != ""; ok != want {
+			t.Errorf("version %d: %s present = %v, want %v", tt.root.Version, tt.key, ok, want)
+		} else if ok && got != ValueHash([]byte(tt.value)) {
+			t.Errorf("version %d: %s has the wrong value", tt.root.Version, tt.key)
+		}
+	}
+}
+
+// reachable returns the nodes under root.
+func reachable(t *testing.T, tree *Tree, root Ref, into map[Ref]bool) {
+	t.Helper()
+	n, err := tree.load(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n == nil {
+		return
+	}
+	into[root] = true
+	if !n.leaf {
+		reachable(t, tree, n.left, into)
+		reachable(t, tree, n.right, into)
+	}
+}
+
+func TestPruneOrphans(t *testing.T) {
+	store := memStore{}
+	tree := NewTree(store)
+	var initial []Entry
+	for _, k := range keys(40) {
+		initial = append(initial, set(k, "1"))
+	}
+	root, err := tree.Update(Ref{}, 1, initial)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updates := [][]Entry{
+		{set("key-0", "2"), set("key-1", "2")},
+		{del("key-2"), del("key-3"), set("new", "3")},
+		{set("key-4", "4"), del("new")},
+	}
+	for i, entries := range updates {
+		root, err = tree.Update(root, uint64(i+2), entries)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, orphan := range tree.Orphans() {
+			if _, ok := store[orphan]; !ok {
+				t.Fatalf("orphan %x is not stored", orphan.Hash[:4])
+			}
+			delete(store, orphan)
+		}
+
+		// pruning keeps exactly the nodes of the latest root
+		live := make(map[Ref]bool)
+		reachable(t, tree, root, live)
+		if len(live) != len(store) {
+			t.Errorf("version %d: %d nodes stored after pruning, %d reachable", i+2, len(store), len(live))
+		}
+	}
+
+	want := map[string]string{"key-0": "2", "key-1": "2", "key-2": "", "key-3": "", "key-4": "4", "key-5": "1", "new": ""}
+	for k, v := range want {
+		got, ok, err := tree.Get(root, Path([]byte(k)))
+		if err != nil {
+			t.Fatalf("reading %s after pruning: %v", k, err)
+		}
+		if ok != (v != "") || (ok && got != ValueHash([]byte(v))) {
+			t.Errorf("%s after pruning: present %v, want value %q", k, ok, v)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package chainstore
+
+import (
+	"errors"
+
+	"github.com/cockroachdb/pebble"
+	chainv1 "github.com/sonata-labs/sonata/gen/chain/v1"
+)
+
+const (
+	UpgradePrefix     = "upgrade/"
+	UpgradePlanKey    = UpgradePrefix + upgradePlanName
+	UpgradeDonePrefix = UpgradePrefix + "done/"
+)
+
+// upgradePlanName is the key of the scheduled upgrade in upgradePlans.
+const upgradePlanName = "plan"
+
+var (
+	// upgradePlans holds the scheduled upgrade under upgradePlanName. Its
+	// prefix takes in UpgradesDone, so it is only read by key.
+	upgradePlans = NewCollection(UpgradePrefix, StringKey, func() *chainv1.UpgradePlan {
+		return &chainv1.UpgradePlan{}
+	})
+
+	// UpgradesDone holds the applied upgrades by name.
+	UpgradesDone = NewCollection(UpgradeDonePrefix, StringKey, func() *chainv1.UpgradeDone {
+		return &chainv1.UpgradeDone{}
+	})
+)
+
+// UpgradeDoneKey returns the state key recording the height an upgrade was
+// applied at.
+func UpgradeDoneKey(name string) []byte {
+	return UpgradesDone.Key(name)
+}
+
+// StoreUpgradePlan stores the scheduled upgrade, replacing any other.
+func (c *ChainStore) StoreUpgradePlan(plan *chainv1.UpgradePlan) error {
+	return upgradePlans.Set(c, upgradePlanName, plan)
+}
+
+// GetUpgradePlan returns the scheduled upgrade, or nil if there is none.
+func (c *ChainStore) GetUpgradePlan() (*chainv1.UpgradePlan, error) {
+	plan, err := upgradePlans.Get(c, upgradePlanName)
+	if errors.Is(err, pebble.ErrNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// DeleteUpgradePlan removes the scheduled upgrade.
+func (c *ChainStore) DeleteUpgradePlan() error {
+	return upgradePlans.Delete(c, upgradePlanName)
+}
+
+// StoreUpgradeDone records that the named upgrade was applied at height.
+func (c *ChainStore) StoreUpgradeDone(name string, height int64) error {
+	return UpgradesDone.Set(c, name, &chainv1.UpgradeDone{Name: name, Height: height})
+}
+
+// IterateUpgradesDone calls fn with every applied upgrade in name order.
+func (c *ChainStore) IterateUpgradesDone(fn func(name string, height int64) error) error {
+	return UpgradesDone.Iterate(c, func(name string, done *chainv1.UpgradeDone) error {
+		return fn(name, done.Height)
+	})
+}
+
+// GetUpgradeDone returns the height the named upgrade was applied at, and
+// false if it has not been applied.
+func (c *ChainStore) GetUpgradeDone(name string) (int64, bool, error) {
+	done, err := UpgradesDone.Get(c, name)
+	if errors.Is(err, pebble.ErrNotFound) {
+		return 0, false, nil
+	} else if err != nil {
+		return 0, false, err
+	}
+	return done.Height, true, nil
+}
@@ -1,73 +1,118 @@
 package chainstore
 
 import (
+	"bytes"
+	"context"
+
 	storagev1 "github.com/sonata-labs/sonata/gen/storage/v1"
-	"google.golang.org/protobuf/proto"
 )
 
 const (
 	UploadPrefix         = "upload/"
 	UploadOriginalPrefix = "upload_idx/original/"
+	UploadUploaderPrefix = "upload_idx/uploader/"
 )
 
-func uploadKey(cid string) []byte {
-	return []byte(UploadPrefix + cid)
-}
+var (
+	// UploadsByOriginalCID indexes uploads by the CID of their original file.
+	UploadsByOriginalCID = NewUniqueIndex(UploadOriginalPrefix, StringKey, StringKey,
+		func(upload *storagev1.FileUploadMessage) (string, bool) {
+			return upload.OriginalCid, true
+		})
 
-func uploadOriginalIndexKey(originalCID string) []byte {
-	return []byte(UploadOriginalPrefix + originalCID)
-}
+	// UploadsByUploader groups uploads by the address of their uploader.
+	UploadsByUploader = NewMultiIndex(UploadUploaderPrefix, StringKey, StringKey,
+		func(upload *storagev1.FileUploadMessage) (string, bool) {
+			return upload.UploaderAddress, upload.UploaderAddress != ""
+		})
 
-// StoreUpload stores a file upload record and creates an index by original CID.
-func (c *ChainStore) StoreUpload(upload *storagev1.FileUploadMessage) error {
-	if err := c.RequireBatch(); err != nil {
-		return err
-	}
+	// Uploads holds the file uploads by transcoded CID.
+	Uploads = NewCollection(UploadPrefix, StringKey, func() *storagev1.FileUploadMessage {
+		return &storagev1.FileUploadMessage{}
+	}, UploadsByOriginalCID, UploadsByUploader)
+)
 
-	uploadBytes, err := proto.Marshal(upload)
-	if err != nil {
-		return err
-	}
+// UploaderIndexUpgrade is the upgrade that indexes existing uploads by
+// uploader, moving the chain store to schema version 2.
+const UploaderIndexUpgrade = "uploader-index"
 
-	// Store by transcoded CID (primary key)
-	if err := c.writer.Set(uploadKey(upload.TranscodedCid), uploadBytes, nil); err != nil {
+// indexUploaders rebuilds UploadsByUploader from Uploads. Entries written
+// before the upgrade are dropped first, so every upload ends up indexed once
+// whatever the binary that wrote it.
+func indexUploaders(_ context.Context, c *ChainStore) error {
+	var stale [][]byte
+	if err := c.iterate([]byte(UploadUploaderPrefix), func(key, _ []byte) error {
+		stale = append(stale, bytes.Clone(key))
+		return nil
+	}); err != nil {
 		return err
 	}
-
-	// Create index by original CID pointing to transcoded CID
-	if err := c.writer.Set(uploadOriginalIndexKey(upload.OriginalCid), []byte(upload.TranscodedCid), nil); err != nil {
-		return err
+	for _, key := range stale {
+		if err := c.remove(key); err != nil {
+			return err
+		}
 	}
+	return Uploads.Iterate(c, func(cid string, upload *storagev1.FileUploadMessage) error {
+		return UploadsByUploader.add(c, cid, upload)
+	})
+}
 
-	return nil
+// UploadKey returns the state key of an upload by transcoded CID.
+func UploadKey(cid string) []byte {
+	return Uploads.Key(cid)
 }
 
-// GetUpload retrieves a file upload record by transcoded CID.
-func (c *ChainStore) GetUpload(cid string) (*storagev1.FileUploadMessage, error) {
-	data, closer, err := c.reader.Get(uploadKey(cid))
-	if err != nil {
-		return nil, err
-	}
-	defer closer.Close()
+// UploadOriginalIndexKey returns the state key mapping an original CID to
+// its transcoded CID.
+func UploadOriginalIndexKey(originalCID string) []byte {
+	return UploadsByOriginalCID.Key(originalCID)
+}
 
-	upload := &storagev1.FileUploadMessage{}
-	if err := proto.Unmarshal(data, upload); err != nil {
-		return nil, err
-	}
+// StoreUpload stores a file upload record and indexes it by original CID.
+func (c *ChainStore) StoreUpload(upload *storagev1.FileUploadMessage) error {
+	return Uploads.Set(c, upload.TranscodedCid, upload)
+}
 
-	return upload, nil
+// GetUpload retrieves a file upload record by transcoded CID.
+func (c *ChainStore) GetUpload(cid string) (*storagev1.FileUploadMessage, error) {
+	return Uploads.Get(c, cid)
 }
 
 // GetUploadByOriginalCID retrieves a file upload record by original CID.
 func (c *ChainStore) GetUploadByOriginalCID(originalCID string) (*storagev1.FileUploadMessage, error) {
-	// Look up the transcoded CID from the index
-	transcodedCIDBytes, closer, err := c.reader.Get(uploadOriginalIndexKey(originalCID))
+	cid, err := UploadsByOriginalCID.Get(c, originalCID)
 	if err != nil {
 		return nil, err
 	}
-	closer.Close()
+	return c.GetUpload(cid)
+}
 
-	// Get the actual upload record
-	return c.GetUpload(string(transcodedCIDBytes))
+// IterateUploads calls fn with every upload in transcoded CID order.
+func (c *ChainStore) IterateUploads(fn func(upload *storagev1.FileUploadMessage) error) error {
+	return Uploads.Iterate(c, func(_ string, upload *storagev1.FileUploadMessage) error {
+		return fn(upload)
+	})
 }
 
+// ListUploads returns a page of uploads in transcoded CID order from cursor
+// on, and the cursor of the next page, or nil after the last page. If
+// uploader is set, only its uploads are listed, through the uploader index.
+func (c *ChainStore) ListUploads(uploader string, cursor []byte, limit int) ([]*storagev1.FileUploadMessage, []byte, error) {
+	if uploader == "" {
+		return Uploads.Page(c, cursor, limit)
+	}
+
+	cids, next, err := UploadsByUploader.Page(c, uploader, cursor, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	uploads := make([]*storagev1.FileUploadMessage, 0, len(cids))
+	for _, cid := range cids {
+		upload, err := Uploads.Get(c, cid)
+		if err != nil {
+			return nil, nil, err
+		}
+		uploads = append(uploads, upload)
+	}
+	return uploads, next, nil
+}
@@ -187,6 +187,16 @@ func (l *LocalStore) GetTranscoded(cid string) ([]byte, error) {
 	return result, nil
 }
 
+// HasTranscoded checks if a transcoded file exists.
+func (l *LocalStore) HasTranscoded(cid string) bool {
+	_, closer, err := l.db.Get(transcodedKey(cid))
+	if err != nil {
+		return false
+	}
+	closer.Close()
+	return true
+}
+
 // DeleteUpload removes the original file and its metadata.
 func (l *LocalStore) DeleteUpload(cid string) error {
 	if err := l.db.Delete(uploadKey(cid), pebble.Sync); err != nil && !errors.Is(err, pebble.ErrNotFound) {
@@ -11,5 +11,14 @@ func NewLocalStore(path string) (*LocalStore, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &LocalStore{db: db}, nil
+	store := &LocalStore{db: db}
+	if err := store.migrate(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+func (l *LocalStore) Close() error {
+	return l.db.Close()
 }
@@ -0,0 +1,167 @@
+package localstore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/pebble"
+)
+
+// SchemaVersionKey records the version of the key layout the local store is
+// written in. A store without the key is at version 1, the layout from before
+// versions were recorded, and has the key written when it is opened.
+const SchemaVersionKey = "meta/schema_version"
+
+// ErrSchemaTooNew is returned for a store written by a newer binary, whose
+// key layout this binary cannot read.
+var ErrSchemaTooNew = errors.New("schema version newer than this binary")
+
+// Migration moves the local store from the schema version before Version to
+// Version. Its writes are committed in one batch with the new version, so an
+// interrupted migration runs again from the start.
+type Migration struct {
+	Version uint64
+	Name    string
+	Migrate func(batch *pebble.Batch) error
+}
+
+// migrations are the local store migrations, in version order. They run when
+// the store is opened.
+var migrations []Migration
+
+// LatestSchemaVersion returns the schema version this binary writes.
+func LatestSchemaVersion() uint64 {
+	if len(migrations) == 0 {
+		return 1
+	}
+	return migrations[len(migrations)-1].Version
+}
+
+// migrate brings the store up to the latest schema version. A new store is
+// written at the latest version from the start.
+func (l *LocalStore) migrate() error {
+	for i, m := range migrations {
+		if m.Version != uint64(i)+2 || m.Migrate == nil {
+			return fmt.Errorf("local store migration %q to schema version %d is invalid", m.Name, m.Version)
+		}
+	}
+
+	version, err := l.schemaVersion()
+	if err != nil {
+		return err
+	}
+	if version > LatestSchemaVersion() {
+		return fmt.Errorf("%w: local store is at version %d, binary at %d", ErrSchemaTooNew, version, LatestSchemaVersion())
+	}
+
+	for _, m := range migrations {
+		if m.Version <= version {
+			continue
+		}
+		batch := l.db.NewIndexedBatch()
+		if err := m.Migrate(batch); err != nil {
+			batch.Close()
+			return fmt.Errorf("migrating local store to schema version %d (%s): %w", m.Version, m.Name, err)
+		}
+		if err := batch.Set([]byte(SchemaVersionKey), binary.BigEndian.AppendUint64(nil, m.Version), nil); err != nil {
+			batch.Close()
+			return err
+		}
+		if err := batch.Commit(pebble.Sync); err != nil {
+			return err
+		}
+		version = m.Version
+	}
+	return nil
+}
+
+// baselinePrefixes are the key prefixes of the version 1 layout, written
+// before versions were recorded.
+var baselinePrefixes = []string{UploadPrefix, UploadMetaPrefix, TranscodedPrefix}
+
+// schemaVersion returns the schema version of the store. A store without the
+// version key is recorded at the latest version if it is empty, and at version
+// 1 if its keys are in the version 1 layout, so the version is known from then
+// on.
+func (l *LocalStore) schemaVersion() (uint64, error) {
+	data, closer, err := l.db.Get([]byte(SchemaVersionKey))
+	if err == nil {
+		defer closer.Close()
+		if len(data) != 8 {
+			return 0, fmt.Errorf("malformed schema version")
+		}
+		return binary.BigEndian.Uint64(data), nil
+	} else if !errors.Is(err, pebble.ErrNotFound) {
+		return 0, err
+	}
+
+	empty, err := l.empty()
+	if err != nil {
+		return 0, err
+	}
+	version := LatestSchemaVersion()
+	if !empty {
+		if err := l.checkBaseline(); err != nil {
+			return 0, err
+		}
+		version = 1
+	}
+	if err := l.db.Set([]byte(SchemaVersionKey), binary.BigEndian.AppendUint64(nil, version), pebble.Sync); err != nil {
+		return 0, err
+	}
+	return version, nil
+}
+
+// checkBaseline returns an error if the store holds a key outside the version
+// 1 layout, which a store without a version key cannot have been written in.
+// It seeks past each prefix it finds rather than reading every key.
+func (l *LocalStore) checkBaseline() error {
+	iter, err := l.db.NewIter(nil)
+	if err != nil {
+		return err
+	}
+	for valid := iter.First(); valid; {
+		prefix, ok := matchPrefix(iter.Key(), baselinePrefixes)
+		if !ok {
+			key := string(iter.Key())
+			iter.Close()
+			return fmt.Errorf("local store has no schema version but key %q is not in the version 1 layout", key)
+		}
+		valid = iter.SeekGE(prefixEnd([]byte(prefix)))
+	}
+	return iter.Close()
+}
+
+// matchPrefix returns the first of prefixes that key starts with.
+func matchPrefix(key []byte, prefixes []string) (string, bool) {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(string(key), prefix) {
+			return prefix, true
+		}
+	}
+	return "", false
+}
+
+// prefixEnd returns the first key after every key starting with prefix.
+func prefixEnd(prefix []byte) []byte {
+	end := bytes.Clone(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return nil
+}
+
+func (l *LocalStore) empty() (bool, error) {
+	iter, err := l.db.NewIter(nil)
+	if err != nil {
+		return false, err
+	}
+	empty := !iter.First()
+	return empty, iter.Close()
+}
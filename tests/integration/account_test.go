@@ -4,15 +4,25 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"connectrpc.com/connect"
+	abciv1 "github.com/cometbft/cometbft/api/cometbft/abci/v1"
+	"github.com/cometbft/cometbft/crypto"
+	"github.com/cometbft/cometbft/crypto/ed25519"
+	cmtp2p "github.com/cometbft/cometbft/p2p"
+	rpchttp "github.com/cometbft/cometbft/rpc/client/http"
 	"github.com/cosmos/gogoproto/proto"
+	"github.com/sonata-labs/sonata/config"
 	accountv1 "github.com/sonata-labs/sonata/gen/account/v1"
 	v1 "github.com/sonata-labs/sonata/gen/api/v1"
 	chainv1 "github.com/sonata-labs/sonata/gen/chain/v1"
+	storagev1 "github.com/sonata-labs/sonata/gen/storage/v1"
 	"github.com/sonata-labs/sonata/sdk"
+	"github.com/sonata-labs/sonata/types/signing"
 )
 
 func getNodeURL() string {
@@ -22,35 +32,109 @@ func getNodeURL() string {
 	return "http://localhost:8080"
 }
 
-// buildCreateAccountTx constructs a signed transaction for creating an account.
-func buildCreateAccountTx(account *accountv1.Account) ([]byte, error) {
-	signedTx := &chainv1.SignedTransaction{
-		Transaction: &chainv1.Transaction{
-			Header: &chainv1.TransactionHeader{
-				ChainId:   "sonata-test",
-				Nonce:     1,
-				GasPrice:  1,
-				GasLimit:  100000,
-				Timeout:   uint64(time.Now().Add(time.Hour).Unix()),
-				Sender:    account.Address,
-				Recipient: "",
-			},
-			Body: &chainv1.TransactionBody{
-				Body: &chainv1.TransactionBody_CreateAccount{
-					CreateAccount: &chainv1.CreateAccountTransaction{
-						Account: account,
-					},
+// getChainID returns the chain id transactions are signed for, asking the
+// node for its network if SONATA_CHAIN_ID is not set. Nodes are initialized
+// with a unique chain id by default.
+func getChainID() string {
+	if chainID := os.Getenv("SONATA_CHAIN_ID"); chainID != "" {
+		return chainID
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	comet, err := rpchttp.New(getCometURL())
+	if err != nil {
+		return ""
+	}
+	status, err := comet.Status(ctx)
+	if err != nil {
+		return ""
+	}
+	return status.NodeInfo.Network
+}
+
+// getFunderKey returns the key of the account that sends and pays for the
+// accounts the tests create: the node's own account, which `sonata init`
+// funds in genesis. The node's home is read from SONATA_HOME.
+func getFunderKey() (crypto.PrivKey, error) {
+	home := os.Getenv("SONATA_HOME")
+	if home == "" {
+		home = config.DefaultHomeDirPath()
+	}
+	nodeKey, err := cmtp2p.LoadNodeKey(filepath.Join(home, "config", "node_key.json"))
+	if err != nil {
+		return nil, fmt.Errorf("loading the funding node key: %w", err)
+	}
+	return nodeKey.PrivKey, nil
+}
+
+// buildCreateAccountTx constructs a transaction creating account, sent and
+// signed by the funding account.
+func buildCreateAccountTx(ctx context.Context, client *sdk.SonataSDK, account *accountv1.Account) ([]byte, error) {
+	tx, key, err := newCreateAccountTx(ctx, client, account)
+	if err != nil {
+		return nil, err
+	}
+	return signTx(tx, key)
+}
+
+// newCreateAccountTx returns an unsigned transaction creating account, sent
+// by the funding account with its next nonce, and the key to sign it with.
+func newCreateAccountTx(ctx context.Context, client *sdk.SonataSDK, account *accountv1.Account) (*chainv1.Transaction, crypto.PrivKey, error) {
+	key, err := getFunderKey()
+	if err != nil {
+		return nil, nil, err
+	}
+	funder, err := client.Account.GetAccount(ctx, connect.NewRequest(&v1.GetAccountRequest{
+		Address: key.PubKey().Address().String(),
+	}))
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting the funding account: %w", err)
+	}
+
+	return &chainv1.Transaction{
+		Header: &chainv1.TransactionHeader{
+			ChainId:   getChainID(),
+			Nonce:     funder.Msg.Account.Nonce + 1,
+			GasPrice:  1,
+			GasLimit:  100000,
+			Timeout:   uint64(time.Now().Add(time.Hour).Unix()),
+			Sender:    funder.Msg.Account.Address,
+			Recipient: "",
+		},
+		Body: &chainv1.TransactionBody{
+			Body: &chainv1.TransactionBody_CreateAccount{
+				CreateAccount: &chainv1.CreateAccountTransaction{
+					Account: account,
 				},
 			},
 		},
-		Signature: &chainv1.TransactionSignature{
-			Signature: []byte("test-signature"), // Placeholder for tests
-		},
-	}
+	}, key, nil
+}
 
+func signTx(tx *chainv1.Transaction, key crypto.PrivKey) ([]byte, error) {
+	signedTx, err := signing.Sign(tx, key)
+	if err != nil {
+		return nil, err
+	}
 	return proto.Marshal(signedTx)
 }
 
+// hasEventAttribute reports whether events contain an event of the given type
+// with the attribute key set to value.
+func hasEventAttribute(events []abciv1.Event, eventType, key, value string) bool {
+	for _, event := range events {
+		if event.Type != eventType {
+			continue
+		}
+		for _, attr := range event.Attributes {
+			if attr.Key == key && attr.Value == value {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func TestCreateAndGetAccount(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
@@ -60,16 +144,28 @@ func TestCreateAndGetAccount(t *testing.T) {
 	client := sdk.NewSonataSDK(nodeURL)
 
 	// Create a unique test account
-	testAddress := fmt.Sprintf("sonata1test%d", time.Now().UnixNano())
+	testKey := ed25519.GenPrivKey()
+	testAddress := testKey.PubKey().Address().String()
 	testAccount := &accountv1.Account{
 		Address: testAddress,
-		PubKey:  "test-pub-key",
-		Balance: 1000,
+		PubKey:  signing.EncodePubKey(testKey.PubKey()),
+		Balance: 0,
 		Nonce:   0,
 	}
 
+	// the funding account pays for the creation
+	funderKey, err := getFunderKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	funderAddress := funderKey.PubKey().Address().String()
+	funderBefore, err := client.Account.GetAccount(ctx, connect.NewRequest(&v1.GetAccountRequest{Address: funderAddress}))
+	if err != nil {
+		t.Fatalf("failed to get funding account: %v", err)
+	}
+
 	// Build the transaction
-	txBytes, err := buildCreateAccountTx(testAccount)
+	txBytes, err := buildCreateAccountTx(ctx, client, testAccount)
 	if err != nil {
 		t.Fatalf("failed to build create account transaction: %v", err)
 	}
@@ -86,6 +182,29 @@ func TestCreateAndGetAccount(t *testing.T) {
 
 	t.Logf("transaction sent, hash: %s", sendResp.Msg.TxHash)
 
+	// the transaction result carries the typed create_account event
+	txResp, err := client.Chain.GetTransaction(ctx, connect.NewRequest(&v1.GetTransactionRequest{
+		TxHash: sendResp.Msg.TxHash,
+	}))
+	if err != nil {
+		t.Fatalf("failed to get transaction: %v", err)
+	}
+	if !hasEventAttribute(txResp.Msg.TxResult.GetEvents(), "create_account", "address", testAddress) {
+		t.Errorf("create_account event for %s not found in %v", testAddress, txResp.Msg.TxResult.GetEvents())
+	}
+	if !hasEventAttribute(txResp.Msg.TxResult.GetEvents(), "create_account", "sender", funderAddress) {
+		t.Errorf("create_account event sent by %s not found in %v", funderAddress, txResp.Msg.TxResult.GetEvents())
+	}
+
+	// the fee of gas_limit * gas_price is taken from the funding account
+	funderAfter, err := client.Account.GetAccount(ctx, connect.NewRequest(&v1.GetAccountRequest{Address: funderAddress}))
+	if err != nil {
+		t.Fatalf("failed to get funding account: %v", err)
+	}
+	if paid := funderBefore.Msg.Account.Balance - funderAfter.Msg.Account.Balance; paid != 100000 {
+		t.Errorf("funding account paid %d for the creation, want 100000", paid)
+	}
+
 	// Query the account
 	getReq := connect.NewRequest(&v1.GetAccountRequest{
 		Address: testAddress,
@@ -115,9 +234,253 @@ func TestCreateAndGetAccount(t *testing.T) {
 		t.Errorf("balance mismatch: got %d, want %d", retrievedAccount.Balance, testAccount.Balance)
 	}
 
-	if retrievedAccount.Nonce != testAccount.Nonce {
-		t.Errorf("nonce mismatch: got %d, want %d", retrievedAccount.Nonce, testAccount.Nonce)
+	// the new account has not sent a transaction yet
+	if retrievedAccount.Nonce != 0 {
+		t.Errorf("nonce mismatch: got %d, want %d", retrievedAccount.Nonce, 0)
 	}
 
 	t.Logf("successfully created and retrieved account: %s", testAddress)
 }
+
+func TestReplayedTransactionRejected(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client := sdk.NewSonataSDK(getNodeURL())
+
+	testKey := ed25519.GenPrivKey()
+	testAccount := &accountv1.Account{
+		Address: testKey.PubKey().Address().String(),
+		PubKey:  signing.EncodePubKey(testKey.PubKey()),
+	}
+
+	tx, funderKey, err := newCreateAccountTx(ctx, client, testAccount)
+	if err != nil {
+		t.Fatalf("failed to build create account transaction: %v", err)
+	}
+	txBytes, err := signTx(tx, funderKey)
+	if err != nil {
+		t.Fatalf("failed to sign transaction: %v", err)
+	}
+
+	if _, err := client.Chain.SendTransaction(ctx, connect.NewRequest(&v1.SendTransactionRequest{
+		SignedTransaction: txBytes,
+	})); err != nil {
+		t.Fatalf("failed to send transaction: %v", err)
+	}
+
+	// a second transaction reusing the consumed nonce must not execute
+	tx.Header.Timeout++
+	replayBytes, err := signTx(tx, funderKey)
+	if err != nil {
+		t.Fatalf("failed to sign transaction: %v", err)
+	}
+
+	_, err = client.Chain.SendTransaction(ctx, connect.NewRequest(&v1.SendTransactionRequest{
+		SignedTransaction: replayBytes,
+	}))
+	if err == nil || !strings.Contains(err.Error(), "invalid nonce") {
+		t.Fatalf("expected replayed nonce to be rejected, got %v", err)
+	}
+}
+
+func TestCreateAccountForOtherKeyRejected(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client := sdk.NewSonataSDK(getNodeURL())
+
+	// an address belongs to the key it is derived from
+	ownerKey := ed25519.GenPrivKey()
+	squatterKey := ed25519.GenPrivKey()
+	txBytes, err := buildCreateAccountTx(ctx, client, &accountv1.Account{
+		Address: ownerKey.PubKey().Address().String(),
+		PubKey:  signing.EncodePubKey(squatterKey.PubKey()),
+	})
+	if err != nil {
+		t.Fatalf("failed to build create account transaction: %v", err)
+	}
+
+	_, err = client.Chain.SendTransaction(ctx, connect.NewRequest(&v1.SendTransactionRequest{
+		SignedTransaction: txBytes,
+	}))
+	if err == nil || !strings.Contains(err.Error(), "does not match its key") {
+		t.Fatalf("expected account for another key to be rejected, got %v", err)
+	}
+}
+
+func TestUnfundedAccountCreationRejected(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client := sdk.NewSonataSDK(getNodeURL())
+
+	// a new key cannot send its own creation, it has nothing to pay with
+	testKey := ed25519.GenPrivKey()
+	testAccount := &accountv1.Account{
+		Address: testKey.PubKey().Address().String(),
+		PubKey:  signing.EncodePubKey(testKey.PubKey()),
+	}
+	tx, _, err := newCreateAccountTx(ctx, client, testAccount)
+	if err != nil {
+		t.Fatalf("failed to build create account transaction: %v", err)
+	}
+	tx.Header.Sender = testAccount.Address
+	tx.Header.Nonce = 1
+	txBytes, err := signTx(tx, testKey)
+	if err != nil {
+		t.Fatalf("failed to sign transaction: %v", err)
+	}
+
+	_, err = client.Chain.SendTransaction(ctx, connect.NewRequest(&v1.SendTransactionRequest{
+		SignedTransaction: txBytes,
+	}))
+	if err == nil || !strings.Contains(err.Error(), "does not exist") {
+		t.Fatalf("expected self-sent account creation to be rejected, got %v", err)
+	}
+}
+
+func TestExpiredTransactionRejected(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client := sdk.NewSonataSDK(getNodeURL())
+
+	testKey := ed25519.GenPrivKey()
+	tx, funderKey, err := newCreateAccountTx(ctx, client, &accountv1.Account{
+		Address: testKey.PubKey().Address().String(),
+		PubKey:  signing.EncodePubKey(testKey.PubKey()),
+	})
+	if err != nil {
+		t.Fatalf("failed to build create account transaction: %v", err)
+	}
+	tx.Header.Timeout = uint64(time.Now().Add(-time.Minute).Unix())
+
+	txBytes, err := signTx(tx, funderKey)
+	if err != nil {
+		t.Fatalf("failed to sign transaction: %v", err)
+	}
+
+	_, err = client.Chain.SendTransaction(ctx, connect.NewRequest(&v1.SendTransactionRequest{
+		SignedTransaction: txBytes,
+	}))
+	if err == nil || !strings.Contains(err.Error(), "expired") {
+		t.Fatalf("expected expired transaction to be rejected, got %v", err)
+	}
+}
+
+func TestInsufficientFundsRejected(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client := sdk.NewSonataSDK(getNodeURL())
+
+	// new accounts start without a balance
+	testKey := ed25519.GenPrivKey()
+	testAccount := &accountv1.Account{
+		Address: testKey.PubKey().Address().String(),
+		PubKey:  signing.EncodePubKey(testKey.PubKey()),
+	}
+	txBytes, err := buildCreateAccountTx(ctx, client, testAccount)
+	if err != nil {
+		t.Fatalf("failed to build create account transaction: %v", err)
+	}
+	if _, err := client.Chain.SendTransaction(ctx, connect.NewRequest(&v1.SendTransactionRequest{
+		SignedTransaction: txBytes,
+	})); err != nil {
+		t.Fatalf("failed to send transaction: %v", err)
+	}
+
+	tx := &chainv1.Transaction{
+		Header: &chainv1.TransactionHeader{
+			ChainId:  getChainID(),
+			Nonce:    1,
+			GasPrice: 1,
+			GasLimit: 100000,
+			Timeout:  uint64(time.Now().Add(time.Hour).Unix()),
+			Sender:   testAccount.Address,
+		},
+		Body: &chainv1.TransactionBody{
+			Body: &chainv1.TransactionBody_FileUpload{
+				FileUpload: &chainv1.FileUploadTransaction{
+					Msg: &storagev1.FileUploadMessage{
+						UploaderAddress: testAccount.Address,
+						OriginalCid:     "bafkreibroke",
+						TranscodedCid:   "bafkreibroketranscoded",
+					},
+				},
+			},
+		},
+	}
+	uploadBytes, err := signTx(tx, testKey)
+	if err != nil {
+		t.Fatalf("failed to sign transaction: %v", err)
+	}
+
+	_, err = client.Chain.SendTransaction(ctx, connect.NewRequest(&v1.SendTransactionRequest{
+		SignedTransaction: uploadBytes,
+	}))
+	if err == nil || !strings.Contains(err.Error(), "insufficient funds") {
+		t.Fatalf("expected transaction to be rejected for its fee, got %v", err)
+	}
+}
+
+func TestListAccounts(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client := sdk.NewSonataSDK(getNodeURL())
+
+	created := make(map[string]bool)
+	for i := 0; i < 3; i++ {
+		testKey := ed25519.GenPrivKey()
+		testAccount := &accountv1.Account{
+			Address: testKey.PubKey().Address().String(),
+			PubKey:  signing.EncodePubKey(testKey.PubKey()),
+		}
+		txBytes, err := buildCreateAccountTx(ctx, client, testAccount)
+		if err != nil {
+			t.Fatalf("failed to build create account transaction: %v", err)
+		}
+		if _, err := client.Chain.SendTransaction(ctx, connect.NewRequest(&v1.SendTransactionRequest{
+			SignedTransaction: txBytes,
+		})); err != nil {
+			t.Fatalf("failed to send transaction: %v", err)
+		}
+		created[testAccount.Address] = true
+	}
+
+	// page through every account two at a time
+	var cursor []byte
+	var previous string
+	for pages := 0; ; pages++ {
+		if pages > 10000 {
+			t.Fatal("listing did not end")
+		}
+		resp, err := client.Account.ListAccounts(ctx, connect.NewRequest(&v1.ListAccountsRequest{
+			Cursor: cursor,
+			Limit:  2,
+		}))
+		if err != nil {
+			t.Fatalf("failed to list accounts: %v", err)
+		}
+		if len(resp.Msg.Accounts) > 2 {
+			t.Fatalf("page holds %d accounts, limit 2", len(resp.Msg.Accounts))
+		}
+		for _, account := range resp.Msg.Accounts {
+			if account.Address <= previous {
+				t.Fatalf("account %s listed after %s", account.Address, previous)
+			}
+			previous = account.Address
+			delete(created, account.Address)
+		}
+		if len(resp.Msg.NextCursor) == 0 {
+			break
+		}
+		cursor = resp.Msg.NextCursor
+	}
+
+	if len(created) != 0 {
+		t.Errorf("accounts not listed: %v", created)
+	}
+}
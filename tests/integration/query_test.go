@@ -0,0 +1,238 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/cometbft/cometbft/crypto/ed25519"
+	rpcclient "github.com/cometbft/cometbft/rpc/client"
+	rpchttp "github.com/cometbft/cometbft/rpc/client/http"
+	"github.com/cosmos/gogoproto/proto"
+	accountv1 "github.com/sonata-labs/sonata/gen/account/v1"
+	v1 "github.com/sonata-labs/sonata/gen/api/v1"
+	"github.com/sonata-labs/sonata/sdk"
+	"github.com/sonata-labs/sonata/store/chainstore"
+	"github.com/sonata-labs/sonata/types/signing"
+)
+
+func getCometURL() string {
+	if url := os.Getenv("SONATA_COMET_URL"); url != "" {
+		return url
+	}
+	return "http://localhost:26657"
+}
+
+// appHashAfter waits for the block following height and returns its app
+// hash, which commits to the state at height.
+func appHashAfter(ctx context.Context, comet *rpchttp.HTTP, height int64) ([]byte, error) {
+	next := height + 1
+	for {
+		block, err := comet.Block(ctx, &next)
+		if err == nil {
+			return block.Block.AppHash, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting for block %d: %w", next, err)
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+}
+
+// txHeight returns the height a committed transaction was included at,
+// waiting for the node to index it.
+func txHeight(ctx context.Context, client *sdk.SonataSDK, hash string) (int64, error) {
+	for {
+		resp, err := client.Chain.GetTransaction(ctx, connect.NewRequest(&v1.GetTransactionRequest{TxHash: hash}))
+		if err == nil {
+			return resp.Msg.Height, nil
+		}
+		select {
+		case <-ctx.Done():
+			return 0, err
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+}
+
+func TestQueryAccountWithProof(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client := sdk.NewSonataSDK(getNodeURL())
+	comet, err := rpchttp.New(getCometURL())
+	if err != nil {
+		t.Fatalf("failed to create comet client: %v", err)
+	}
+
+	testKey := ed25519.GenPrivKey()
+	testAccount := &accountv1.Account{
+		Address: testKey.PubKey().Address().String(),
+		PubKey:  signing.EncodePubKey(testKey.PubKey()),
+	}
+	txBytes, err := buildCreateAccountTx(ctx, client, testAccount)
+	if err != nil {
+		t.Fatalf("failed to build create account transaction: %v", err)
+	}
+	if _, err := client.Chain.SendTransaction(ctx, connect.NewRequest(&v1.SendTransactionRequest{
+		SignedTransaction: txBytes,
+	})); err != nil {
+		t.Fatalf("failed to send transaction: %v", err)
+	}
+
+	// an existing account is returned with a proof of inclusion
+	res, err := comet.ABCIQueryWithOptions(ctx, "/account/"+testAccount.Address, nil, rpcclient.ABCIQueryOptions{Prove: true})
+	if err != nil {
+		t.Fatalf("failed to query account: %v", err)
+	}
+	resp := res.Response
+	if resp.Code != 0 {
+		t.Fatalf("query failed: %s", resp.Log)
+	}
+	account := &accountv1.Account{}
+	if err := proto.Unmarshal(resp.Value, account); err != nil {
+		t.Fatalf("failed to decode account: %v", err)
+	}
+	if account.Address != testAccount.Address || account.PubKey != testAccount.PubKey {
+		t.Errorf("unexpected account: %s", account.String())
+	}
+	if resp.ProofOps == nil || len(resp.ProofOps.Ops) != 1 {
+		t.Fatalf("expected one proof op, got %v", resp.ProofOps)
+	}
+
+	appHash, err := appHashAfter(ctx, comet, resp.Height)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := chainstore.VerifyProofOp(&resp.ProofOps.Ops[0], appHash, resp.Value); err != nil {
+		t.Errorf("inclusion proof did not verify: %v", err)
+	}
+
+	// a missing account is returned with a proof of absence
+	res, err = comet.ABCIQueryWithOptions(ctx, "/account/sonata1missing", nil, rpcclient.ABCIQueryOptions{Prove: true})
+	if err != nil {
+		t.Fatalf("failed to query account: %v", err)
+	}
+	resp = res.Response
+	if resp.Value != nil {
+		t.Fatalf("expected no value for missing account, got %x", resp.Value)
+	}
+	if resp.ProofOps == nil || len(resp.ProofOps.Ops) != 1 {
+		t.Fatalf("expected one proof op, got %v", resp.ProofOps)
+	}
+
+	appHash, err = appHashAfter(ctx, comet, resp.Height)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := chainstore.VerifyProofOp(&resp.ProofOps.Ops[0], appHash, nil); err != nil {
+		t.Errorf("absence proof did not verify: %v", err)
+	}
+	if err := chainstore.VerifyProofOp(&resp.ProofOps.Ops[0], appHash, []byte("forged")); err == nil {
+		t.Error("absence proof verified a forged value")
+	}
+
+	// an unknown route is rejected
+	res, err = comet.ABCIQueryWithOptions(ctx, "/account", nil, rpcclient.ABCIQueryOptions{})
+	if err != nil {
+		t.Fatalf("failed to query: %v", err)
+	}
+	if res.Response.Code == 0 {
+		t.Error("expected query without an address to fail")
+	}
+}
+
+func TestQueryAccountAtHeight(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client := sdk.NewSonataSDK(getNodeURL())
+	comet, err := rpchttp.New(getCometURL())
+	if err != nil {
+		t.Fatalf("failed to create comet client: %v", err)
+	}
+
+	testKey := ed25519.GenPrivKey()
+	testAccount := &accountv1.Account{
+		Address: testKey.PubKey().Address().String(),
+		PubKey:  signing.EncodePubKey(testKey.PubKey()),
+	}
+	txBytes, err := buildCreateAccountTx(ctx, client, testAccount)
+	if err != nil {
+		t.Fatalf("failed to build create account transaction: %v", err)
+	}
+	sendResp, err := client.Chain.SendTransaction(ctx, connect.NewRequest(&v1.SendTransactionRequest{
+		SignedTransaction: txBytes,
+	}))
+	if err != nil {
+		t.Fatalf("failed to send transaction: %v", err)
+	}
+	created, err := txHeight(ctx, client, sendResp.Msg.TxHash)
+	if err != nil {
+		t.Fatalf("failed to get transaction: %v", err)
+	}
+
+	// wait for a later block so the creation height is in the past
+	if _, err := appHashAfter(ctx, comet, created+1); err != nil {
+		t.Fatal(err)
+	}
+
+	// the account did not exist before the block creating it
+	res, err := comet.ABCIQueryWithOptions(ctx, "/account/"+testAccount.Address, nil, rpcclient.ABCIQueryOptions{Height: created - 1, Prove: true})
+	if err != nil {
+		t.Fatalf("failed to query account: %v", err)
+	}
+	resp := res.Response
+	if resp.Code != 0 {
+		t.Fatalf("query at height %d failed: %s", created-1, resp.Log)
+	}
+	if resp.Height != created-1 || resp.Value != nil {
+		t.Fatalf("expected no account at height %d, got height %d value %x", created-1, resp.Height, resp.Value)
+	}
+	appHash, err := appHashAfter(ctx, comet, created-1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := chainstore.VerifyProofOp(&resp.ProofOps.Ops[0], appHash, nil); err != nil {
+		t.Errorf("absence proof at height %d did not verify: %v", created-1, err)
+	}
+
+	// and exists from it on, proven against that height's app hash
+	res, err = comet.ABCIQueryWithOptions(ctx, "/account/"+testAccount.Address, nil, rpcclient.ABCIQueryOptions{Height: created, Prove: true})
+	if err != nil {
+		t.Fatalf("failed to query account: %v", err)
+	}
+	resp = res.Response
+	if resp.Code != 0 || resp.Value == nil {
+		t.Fatalf("expected account at height %d: %s", created, resp.Log)
+	}
+	appHash, err = appHashAfter(ctx, comet, created)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := chainstore.VerifyProofOp(&resp.ProofOps.Ops[0], appHash, resp.Value); err != nil {
+		t.Errorf("inclusion proof at height %d did not verify: %v", created, err)
+	}
+
+	// the account API reads the same heights
+	if _, err := client.Account.GetAccount(ctx, connect.NewRequest(&v1.GetAccountRequest{
+		Address: testAccount.Address,
+		Height:  created - 1,
+	})); connect.CodeOf(err) != connect.CodeNotFound {
+		t.Errorf("expected not found at height %d, got %v", created-1, err)
+	}
+	getResp, err := client.Account.GetAccount(ctx, connect.NewRequest(&v1.GetAccountRequest{
+		Address: testAccount.Address,
+		Height:  created,
+	}))
+	if err != nil {
+		t.Fatalf("failed to get account at height %d: %v", created, err)
+	}
+	if getResp.Msg.Account.Address != testAccount.Address {
+		t.Errorf("unexpected account at height %d: %s", created, getResp.Msg.Account.String())
+	}
+}
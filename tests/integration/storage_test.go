@@ -2,6 +2,7 @@ package integration
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -266,3 +267,59 @@ func TestChunkedUploadInOrder(t *testing.T) {
 	t.Logf("in-order chunked upload successful: transcoded=%s", finalResp.Msg.TranscodedCid)
 }
 
+
+// TestListUploads tests that an upload is listed once it is on chain.
+func TestListUploads(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	client := sdk.NewSonataSDK(getNodeURL())
+
+	testData := []byte(fmt.Sprintf("list uploads %d", time.Now().UnixNano()))
+	expectedCID, err := cid.Compute(testData)
+	if err != nil {
+		t.Fatalf("failed to compute CID: %v", err)
+	}
+	uploadResp, err := client.Storage.Upload(ctx, connect.NewRequest(&v1.UploadRequest{
+		Cid:  expectedCID,
+		Data: testData,
+		Metadata: &v1.FileMetadata{
+			FileName: "test-list.flac",
+			MimeType: "audio/flac",
+			Size:     uint64(len(testData)),
+		},
+	}))
+	if err != nil {
+		t.Fatalf("failed to upload file: %v", err)
+	}
+
+	// Wait for block finalization
+	time.Sleep(2 * time.Second)
+
+	var cursor []byte
+	for pages := 0; ; pages++ {
+		if pages > 10000 {
+			t.Fatal("listing did not end")
+		}
+		resp, err := client.Storage.ListUploads(ctx, connect.NewRequest(&v1.ListUploadsRequest{
+			Cursor: cursor,
+			Limit:  10,
+		}))
+		if err != nil {
+			t.Fatalf("failed to list uploads: %v", err)
+		}
+		for _, upload := range resp.Msg.Uploads {
+			if upload.TranscodedCid == uploadResp.Msg.TranscodedCid {
+				if upload.OriginalCid != expectedCID {
+					t.Errorf("original CID mismatch: got %s, want %s", upload.OriginalCid, expectedCID)
+				}
+				return
+			}
+		}
+		if len(resp.Msg.NextCursor) == 0 {
+			break
+		}
+		cursor = resp.Msg.NextCursor
+	}
+	t.Errorf("upload %s not listed", uploadResp.Msg.TranscodedCid)
+}
@@ -0,0 +1,60 @@
+package module
+
+import (
+	"context"
+	"time"
+
+	abcitypes "github.com/cometbft/cometbft/abci/types"
+)
+
+// BlockHeader describes the block being finalized.
+type BlockHeader struct {
+	Height          int64
+	Time            time.Time
+	Hash            []byte
+	ProposerAddress []byte
+}
+
+// BlockResult is what a block hook adds to the block's FinalizeBlock
+// response.
+type BlockResult struct {
+	Events           []abcitypes.Event
+	ValidatorUpdates []abcitypes.ValidatorUpdate
+}
+
+// BeginBlocker is a module with logic run at the start of every block,
+// before its transactions are executed.
+//
+// Like FinalizeBlock, the hook writes to its own branch of the block batch,
+// set with SetChainStoreBatch before it runs. An error fails the block and
+// halts the node, so a hook only returns one when it cannot run the same as
+// on every other validator.
+type BeginBlocker interface {
+	Module
+	BeginBlock(ctx context.Context, header BlockHeader) (*BlockResult, error)
+}
+
+// EndBlocker is a module with logic run at the end of every block, after its
+// transactions and the modules' FinalizeBlock. The block header is available
+// from the context with BlockHeaderFromContext.
+//
+// Writes follow the same rules as BeginBlocker.
+type EndBlocker interface {
+	Module
+	EndBlock(ctx context.Context) (*BlockResult, error)
+}
+
+type blockHeaderKey struct{}
+
+// ContextWithBlockHeader returns a context carrying the header of the block
+// being finalized.
+func ContextWithBlockHeader(ctx context.Context, header BlockHeader) context.Context {
+	return context.WithValue(ctx, blockHeaderKey{}, header)
+}
+
+// BlockHeaderFromContext returns the header of the block being finalized,
+// if ctx belongs to FinalizeBlock.
+func BlockHeaderFromContext(ctx context.Context) (BlockHeader, bool) {
+	header, ok := ctx.Value(blockHeaderKey{}).(BlockHeader)
+	return header, ok
+}
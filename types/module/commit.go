@@ -0,0 +1,36 @@
+package module
+
+import (
+	"context"
+
+	chainv1 "github.com/sonata-labs/sonata/gen/chain/v1"
+)
+
+// CommittedBlock is a block whose state has been durably committed to the
+// chain store.
+type CommittedBlock struct {
+	Height  int64
+	AppHash []byte
+
+	// Events are the typed events of the block's successful transactions,
+	// in execution order.
+	Events []*chainv1.TransactionEvent
+}
+
+// CommitHook runs side effects that must only happen once a block is
+// committed, such as local store cleanup, notifications or cache
+// invalidation. It must not write chain state. A failing hook is logged and
+// does not stop the chain, and a crash right after a commit skips the hooks
+// for that block, so side effects should be safe to miss.
+type CommitHook func(ctx context.Context, block *CommittedBlock) error
+
+// CommitHookRouter runs commit hooks after every block commit, in
+// registration order.
+type CommitHookRouter interface {
+	RegisterCommitHook(name string, hook CommitHook)
+}
+
+// CommitHookModule is a module with side effects run after blocks commit.
+type CommitHookModule interface {
+	RegisterCommitHooks(router CommitHookRouter)
+}
@@ -0,0 +1,18 @@
+package module
+
+import (
+	"context"
+
+	chainv1 "github.com/sonata-labs/sonata/gen/chain/v1"
+	"github.com/sonata-labs/sonata/store/chainstore"
+)
+
+// GenesisHandler imports a module's part of the genesis app state into store,
+// or exports it from store into state. Imports run in InitChain against the
+// genesis batch, so every validator must produce the same writes.
+type GenesisHandler func(ctx context.Context, store *chainstore.ChainStore, state *chainv1.GenesisState) error
+
+// GenesisRouter runs genesis imports in InitChain, in registration order.
+type GenesisRouter interface {
+	RegisterGenesisHandler(name string, handler GenesisHandler)
+}
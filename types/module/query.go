@@ -0,0 +1,26 @@
+package module
+
+import (
+	"context"
+
+	"github.com/sonata-labs/sonata/store/chainstore"
+)
+
+// QueryHandler resolves the arguments of a query path to the state key the
+// result is read from. The response carries its value, and a proof of it when
+// one is requested. A result read through an index is queried in two steps,
+// the index entry first, so every value returned is proven.
+type QueryHandler func(ctx context.Context, store *chainstore.ChainStore, args []string) ([]byte, error)
+
+// QueryRouter dispatches ABCI queries by path. A handler registered for
+// "/upload" serves "/upload/{cid}", with the remaining path segments passed
+// as args. The longest registered route wins, so "/upload/original" can be
+// served by a different handler.
+type QueryRouter interface {
+	RegisterQueryHandler(route string, handler QueryHandler)
+}
+
+// QueryModule is a module that serves state queries.
+type QueryModule interface {
+	RegisterQueryHandlers(router QueryRouter)
+}
@@ -0,0 +1,131 @@
+package module
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	abcitypes "github.com/cometbft/cometbft/abci/types"
+	chainv1 "github.com/sonata-labs/sonata/gen/chain/v1"
+	"github.com/sonata-labs/sonata/store/chainstore"
+)
+
+// Tx is a decoded transaction handed to the handler registered for its body.
+type Tx struct {
+	// Hash is the CometBFT hash of the raw transaction bytes
+	Hash   []byte
+	Height int64
+
+	// Time is the block time, or the local clock when checking a
+	// transaction for the mempool
+	Time time.Time
+
+	Signed *chainv1.SignedTransaction
+
+	// Store is the transaction's branch of the block batch, or of the
+	// committed state during CheckTx. Writes are discarded if the handler
+	// returns an error.
+	Store *chainstore.ChainStore
+
+	// Gas meters the whole transaction against its header's gas limit.
+	// Store access is charged automatically.
+	Gas *chainstore.GasMeter
+
+	// Simulate is set when the transaction is only checked, for the mempool
+	// or a block proposal, and its body will not run. Ante handlers may then
+	// record what the body would, so the transactions checked after it see
+	// it.
+	Simulate bool
+
+	events      []abcitypes.Event
+	typedEvents []*chainv1.TransactionEvent
+}
+
+// Header returns the transaction header.
+func (t *Tx) Header() *chainv1.TransactionHeader {
+	return t.Signed.GetTransaction().GetHeader()
+}
+
+// Body returns the transaction body.
+func (t *Tx) Body() *chainv1.TransactionBody {
+	return t.Signed.GetTransaction().GetBody()
+}
+
+// HashHex returns the transaction hash as CometBFT displays it.
+func (t *Tx) HashHex() string {
+	return fmt.Sprintf("%X", t.Hash)
+}
+
+// EmitEvent records a typed event for the transaction. It becomes an ABCI
+// event named after the event's field in TransactionEvent, for example
+// file_upload, with one indexed attribute per event field. Events are only
+// published if the transaction succeeds.
+func (t *Tx) EmitEvent(event *chainv1.TransactionEvent) error {
+	m := event.ProtoReflect()
+	field := m.WhichOneof(m.Descriptor().Oneofs().ByName("event"))
+	if field == nil {
+		return errors.New("empty transaction event")
+	}
+
+	body := m.Get(field).Message()
+	fields := body.Descriptor().Fields()
+	attributes := make([]abcitypes.EventAttribute, 0, fields.Len())
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		attributes = append(attributes, abcitypes.EventAttribute{
+			Key:   string(fd.Name()),
+			Value: body.Get(fd).String(),
+			Index: true,
+		})
+	}
+
+	t.events = append(t.events, abcitypes.Event{Type: string(field.Name()), Attributes: attributes})
+	t.typedEvents = append(t.typedEvents, event)
+	return nil
+}
+
+// Events returns the events emitted by the transaction's handlers.
+func (t *Tx) Events() []abcitypes.Event {
+	return t.events
+}
+
+// TransactionEvents returns the events emitted by the transaction's handlers
+// as they were emitted.
+func (t *Tx) TransactionEvents() []*chainv1.TransactionEvent {
+	return t.typedEvents
+}
+
+// MsgHandler executes a single transaction body.
+type MsgHandler func(ctx context.Context, tx *Tx) error
+
+// AnteHandler checks a transaction before its body is executed, both when it
+// enters the mempool and again when it is delivered in a block. Returning an
+// error rejects the transaction.
+type AnteHandler func(ctx context.Context, tx *Tx) error
+
+// MsgRouter dispatches transaction bodies to the module that owns them.
+// Bodies are identified by their oneof wrapper type, for example
+// (*chainv1.TransactionBody_FileUpload)(nil). baseGas is charged before the
+// handler runs, on top of the store access it makes.
+type MsgRouter interface {
+	RegisterMsgHandler(body any, baseGas uint64, handler MsgHandler)
+
+	// RegisterAnteHandler adds a check run on every transaction. Ante
+	// handlers run in registration order.
+	RegisterAnteHandler(handler AnteHandler)
+
+	// RegisterProposalCheck adds a check run on every transaction of a
+	// block proposal, refusing the proposal if one fails. Checks read the
+	// last committed state and must not depend on the other transactions
+	// in the block, so every validator reaches the same verdict. A check
+	// should also be registered as an ante handler to run in the mempool
+	// and when the block is executed.
+	RegisterProposalCheck(check AnteHandler)
+}
+
+// MsgModule is a module that handles transactions or checks every
+// transaction with ante handlers.
+type MsgModule interface {
+	RegisterMsgHandlers(router MsgRouter)
+}
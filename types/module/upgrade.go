@@ -0,0 +1,19 @@
+package module
+
+import (
+	"context"
+
+	chainv1 "github.com/sonata-labs/sonata/gen/chain/v1"
+	"github.com/sonata-labs/sonata/store/chainstore"
+)
+
+// UpgradeHandler migrates the chain store when the chain reaches the height
+// of the upgrade plan it is registered for, before the block's transactions
+// run. Its writes are committed with the block.
+type UpgradeHandler func(ctx context.Context, store *chainstore.ChainStore, plan *chainv1.UpgradePlan) error
+
+// UpgradeRouter registers the upgrades a binary knows how to apply, by plan
+// name. A binary reaching a plan it has no handler for halts.
+type UpgradeRouter interface {
+	RegisterUpgradeHandler(name string, handler UpgradeHandler)
+}
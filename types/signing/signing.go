@@ -0,0 +1,73 @@
+// Package signing defines how Sonata transactions are signed and verified.
+//
+// Accounts are ed25519 keys. The account's pub_key is the hex encoding of
+// the 32 byte public key, and a transaction is signed over its canonical
+// sign bytes: the deterministic protobuf encoding of the Transaction message.
+package signing
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/cometbft/cometbft/crypto"
+	"github.com/cometbft/cometbft/crypto/ed25519"
+	chainv1 "github.com/sonata-labs/sonata/gen/chain/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// ErrInvalidSignature is returned when a signature does not verify.
+var ErrInvalidSignature = errors.New("invalid transaction signature")
+
+// SignBytes returns the bytes a sender signs for tx.
+func SignBytes(tx *chainv1.Transaction) ([]byte, error) {
+	if tx == nil {
+		return nil, errors.New("nil transaction")
+	}
+	return proto.MarshalOptions{Deterministic: true}.Marshal(tx)
+}
+
+// Sign signs tx with key and returns the signed transaction.
+func Sign(tx *chainv1.Transaction, key crypto.PrivKey) (*chainv1.SignedTransaction, error) {
+	signBytes, err := SignBytes(tx)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := key.Sign(signBytes)
+	if err != nil {
+		return nil, fmt.Errorf("signing transaction: %w", err)
+	}
+	return &chainv1.SignedTransaction{
+		Transaction: tx,
+		Signature:   &chainv1.TransactionSignature{Signature: sig},
+	}, nil
+}
+
+// Verify checks the signature of signedTx against pubKey.
+func Verify(signedTx *chainv1.SignedTransaction, pubKey crypto.PubKey) error {
+	signBytes, err := SignBytes(signedTx.GetTransaction())
+	if err != nil {
+		return err
+	}
+	if !pubKey.VerifySignature(signBytes, signedTx.GetSignature().GetSignature()) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// EncodePubKey returns the account pub_key encoding of an ed25519 key.
+func EncodePubKey(pubKey crypto.PubKey) string {
+	return hex.EncodeToString(pubKey.Bytes())
+}
+
+// DecodePubKey parses an account pub_key.
+func DecodePubKey(s string) (crypto.PubKey, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("pub key is not hex: %w", err)
+	}
+	if len(b) != ed25519.PubKeySize {
+		return nil, fmt.Errorf("pub key must be %d bytes, got %d", ed25519.PubKeySize, len(b))
+	}
+	return ed25519.PubKey(b), nil
+}
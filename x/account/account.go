@@ -3,17 +3,28 @@ package account
 import (
 	"context"
 	"errors"
+	"fmt"
+	"math/bits"
+	"time"
 
 	"connectrpc.com/connect"
 	abcitypes "github.com/cometbft/cometbft/abci/types"
-	"github.com/cosmos/gogoproto/proto"
+	"github.com/cometbft/cometbft/crypto"
 	"github.com/sonata-labs/sonata/config"
+	accountv1 "github.com/sonata-labs/sonata/gen/account/v1"
 	v1 "github.com/sonata-labs/sonata/gen/api/v1"
 	"github.com/sonata-labs/sonata/gen/api/v1/v1connect"
 	chainv1 "github.com/sonata-labs/sonata/gen/chain/v1"
 	"github.com/sonata-labs/sonata/store/chainstore"
 	"github.com/sonata-labs/sonata/types/module"
+	"github.com/sonata-labs/sonata/types/signing"
 	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// CreateAccountGas is the base gas for creating an account.
+	CreateAccountGas uint64 = 10000
 )
 
 type AccountService struct {
@@ -34,7 +45,17 @@ func (a *AccountService) GetAccount(ctx context.Context, req *connect.Request[v1
 		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("address is required"))
 	}
 
-	account, err := a.store.GetAccount(address)
+	store := a.store
+	if height := req.Msg.Height; height != 0 {
+		view, err := a.store.AtHeight(height)
+		if err != nil {
+			return nil, connect.NewError(connect.CodeOutOfRange, err)
+		}
+		defer view.Close()
+		store = view
+	}
+
+	account, err := store.GetAccount(address)
 	if err != nil {
 		return nil, connect.NewError(connect.CodeNotFound, err)
 	}
@@ -42,6 +63,69 @@ func (a *AccountService) GetAccount(ctx context.Context, req *connect.Request[v1
 	return connect.NewResponse(&v1.GetAccountResponse{Account: account}), nil
 }
 
+// ListAccounts returns a page of accounts in address order.
+func (a *AccountService) ListAccounts(ctx context.Context, req *connect.Request[v1.ListAccountsRequest]) (*connect.Response[v1.ListAccountsResponse], error) {
+	accounts, next, err := a.store.ListAccounts(req.Msg.Cursor, int(req.Msg.Limit))
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	return connect.NewResponse(&v1.ListAccountsResponse{Accounts: accounts, NextCursor: next}), nil
+}
+
+// CreateAccount returns the unsigned transaction creating an account, for
+// the funding account's key to sign and send.
+func (a *AccountService) CreateAccount(ctx context.Context, req *connect.Request[v1.CreateAccountRequest]) (*connect.Response[v1.CreateAccountResponse], error) {
+	account := req.Msg.Account
+	if account == nil || account.Address == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("account address is required"))
+	}
+	if req.Msg.Funder == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("funding account is required"))
+	}
+	funder, err := a.store.GetAccount(req.Msg.Funder)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("funding account %s: %w", req.Msg.Funder, err))
+	}
+
+	exists, err := a.store.HasAccount(account.Address)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	if exists {
+		return nil, connect.NewError(connect.CodeAlreadyExists, fmt.Errorf("account %s already exists", account.Address))
+	}
+	if _, err := newAccountKey(account); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	params, err := a.store.GetParams()
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	tx := &chainv1.Transaction{
+		Header: &chainv1.TransactionHeader{
+			ChainId:  a.config.Sonata.ChainID,
+			Nonce:    funder.Nonce + 1,
+			GasPrice: params.MinGasPrice,
+			GasLimit: 100000,
+			Timeout:  uint64(time.Now().Add(time.Hour).Unix()),
+			Sender:   funder.Address,
+		},
+		Body: &chainv1.TransactionBody{
+			Body: &chainv1.TransactionBody_CreateAccount{
+				CreateAccount: &chainv1.CreateAccountTransaction{Account: account},
+			},
+		},
+	}
+	txBytes, err := proto.Marshal(tx)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to marshal transaction: %w", err))
+	}
+
+	return connect.NewResponse(&v1.CreateAccountResponse{Transaction: txBytes}), nil
+}
+
 var _ v1connect.AccountHandler = (*AccountService)(nil)
 
 func NewAccountService(config *config.Config, logger *zap.Logger, store *chainstore.ChainStore) *AccountService {
@@ -69,22 +153,243 @@ func (a *AccountService) ProcessProposal(ctx context.Context, req *abcitypes.Pro
 
 func (a *AccountService) FinalizeBlock(ctx context.Context, req *abcitypes.FinalizeBlockRequest) (*abcitypes.FinalizeBlockResponse, error) {
 	a.Logger.Info("finalizing block")
+	return &abcitypes.FinalizeBlockResponse{}, nil
+}
+
+// Transaction Handlers
+
+// RegisterMsgHandlers registers the transaction bodies owned by the account
+// module and the signature check every transaction must pass.
+func (a *AccountService) RegisterMsgHandlers(router module.MsgRouter) {
+	router.RegisterAnteHandler(a.VerifySignature)
+	router.RegisterProposalCheck(a.VerifyProposedSignature)
+	router.RegisterAnteHandler(a.IncrementNonce)
+	router.RegisterAnteHandler(a.DeductFee)
+	router.RegisterAnteHandler(a.RecordCreatedAccount)
+	router.RegisterMsgHandler((*chainv1.TransactionBody_CreateAccount)(nil), CreateAccountGas, a.HandleCreateAccount)
+}
 
-	for _, tx := range req.Txs {
-		var signedTransaction chainv1.SignedTransaction
-		err := proto.Unmarshal(tx, &signedTransaction)
+// Genesis
+
+// InitGenesis imports the genesis accounts. Their balances are the only way
+// funds enter the chain.
+func InitGenesis(ctx context.Context, store *chainstore.ChainStore, state *chainv1.GenesisState) error {
+	for _, account := range state.Accounts {
+		if account.Address == "" {
+			return errors.New("genesis account has no address")
+		}
+		if _, err := signing.DecodePubKey(account.PubKey); err != nil {
+			return fmt.Errorf("genesis account %s: %w", account.Address, err)
+		}
+		exists, err := store.HasAccount(account.Address)
 		if err != nil {
-			return nil, err
+			return err
 		}
-
-		if account := signedTransaction.Transaction.Body.GetCreateAccount(); account != nil {
-			err = a.ChainStoreBatch.StoreAccount(account.Account)
-			if err != nil {
-				return nil, err
-			}
-			a.Logger.Info("created account", "address", account.Account.Address)
+		if exists {
+			return fmt.Errorf("duplicate genesis account %s", account.Address)
+		}
+		if err := store.StoreAccount(account); err != nil {
+			return err
 		}
 	}
+	return nil
+}
 
-	return &abcitypes.FinalizeBlockResponse{}, nil
+// ExportGenesis exports every account in address order.
+func ExportGenesis(ctx context.Context, store *chainstore.ChainStore, state *chainv1.GenesisState) error {
+	return store.IterateAccounts(func(account *accountv1.Account) error {
+		state.Accounts = append(state.Accounts, account)
+		return nil
+	})
+}
+
+// Queries
+
+// RegisterQueryHandlers registers the state queries served by the account module.
+func (a *AccountService) RegisterQueryHandlers(router module.QueryRouter) {
+	router.RegisterQueryHandler("/account", a.QueryAccount)
+}
+
+// QueryAccount serves /account/{address}.
+func (a *AccountService) QueryAccount(ctx context.Context, store *chainstore.ChainStore, args []string) ([]byte, error) {
+	if len(args) != 1 || args[0] == "" {
+		return nil, errors.New("usage: /account/{address}")
+	}
+	return chainstore.AccountKey(args[0]), nil
+}
+
+// VerifySignature checks that a transaction is signed by its sender.
+func (a *AccountService) VerifySignature(ctx context.Context, tx *module.Tx) error {
+	pubKey, err := senderPubKey(tx)
+	if err != nil {
+		return err
+	}
+	return signing.Verify(tx.Signed, pubKey)
+}
+
+// VerifyProposedSignature checks the signature of a transaction in a block
+// proposal whose sender's account exists before the block executes. Other
+// senders may be created earlier in the same block, so their transactions are
+// verified when the block executes instead.
+func (a *AccountService) VerifyProposedSignature(ctx context.Context, tx *module.Tx) error {
+	exists, err := tx.Store.HasAccount(tx.Header().GetSender())
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return nil
+	}
+	return a.VerifySignature(ctx, tx)
+}
+
+// senderPubKey returns the key a transaction must be signed with, that of
+// its sender's account.
+func senderPubKey(tx *module.Tx) (crypto.PubKey, error) {
+	sender := tx.Header().GetSender()
+	if sender == "" {
+		return nil, errors.New("transaction has no sender")
+	}
+
+	exists, err := tx.Store.HasAccount(sender)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("sender account %s does not exist", sender)
+	}
+
+	account, err := tx.Store.GetAccount(sender)
+	if err != nil {
+		return nil, err
+	}
+	pubKey, err := signing.DecodePubKey(account.PubKey)
+	if err != nil {
+		return nil, fmt.Errorf("sender account %s: %w", sender, err)
+	}
+	return pubKey, nil
+}
+
+// newAccountKey returns the key of an account a transaction creates. An
+// account's address is derived from its key, so no one can take the address
+// of a key they do not hold.
+func newAccountKey(account *accountv1.Account) (crypto.PubKey, error) {
+	pubKey, err := signing.DecodePubKey(account.PubKey)
+	if err != nil {
+		return nil, err
+	}
+	if address := pubKey.Address().String(); account.Address != address {
+		return nil, fmt.Errorf("account address %s does not match its key, expected %s", account.Address, address)
+	}
+	return pubKey, nil
+}
+
+// IncrementNonce requires a transaction to carry its sender's next nonce and
+// consumes it, so a signed transaction can only be executed once. The nonce
+// stays consumed even if the transaction body later fails.
+func (a *AccountService) IncrementNonce(ctx context.Context, tx *module.Tx) error {
+	sender := tx.Header().GetSender()
+	nonce := tx.Header().GetNonce()
+
+	account, err := tx.Store.GetAccount(sender)
+	if err != nil {
+		return err
+	}
+	if nonce != account.Nonce+1 {
+		return fmt.Errorf("invalid nonce %d, expected %d", nonce, account.Nonce+1)
+	}
+	account.Nonce = nonce
+	return tx.Store.StoreAccount(account)
+}
+
+// DeductFee charges the sender gas_limit * gas_price before the body runs.
+// The fee is burned and is not refunded if the body fails or uses less gas.
+func (a *AccountService) DeductFee(ctx context.Context, tx *module.Tx) error {
+	header := tx.Header()
+	params, err := tx.Store.GetParams()
+	if err != nil {
+		return err
+	}
+	if header.GetGasPrice() < params.MinGasPrice {
+		return fmt.Errorf("gas price %d below minimum %d", header.GetGasPrice(), params.MinGasPrice)
+	}
+
+	overflow, fee := bits.Mul64(header.GetGasLimit(), header.GetGasPrice())
+	if overflow != 0 {
+		return errors.New("fee overflows")
+	}
+
+	account, err := tx.Store.GetAccount(header.GetSender())
+	if err != nil {
+		return err
+	}
+	if account.Balance < fee {
+		return fmt.Errorf("insufficient funds: balance %d, fee %d", account.Balance, fee)
+	}
+	account.Balance -= fee
+	return tx.Store.StoreAccount(account)
+}
+
+// RecordCreatedAccount checks the key of the account a simulated transaction
+// creates and stores the account, so the mempool and proposals refuse a
+// second transaction creating it and accept transactions the new account
+// sends. When the block executes, the account is stored by
+// HandleCreateAccount instead.
+func (a *AccountService) RecordCreatedAccount(ctx context.Context, tx *module.Tx) error {
+	created := tx.Body().GetCreateAccount().GetAccount()
+	if !tx.Simulate || created == nil {
+		return nil
+	}
+	if _, err := newAccountKey(created); err != nil {
+		return err
+	}
+	exists, err := tx.Store.HasAccount(created.Address)
+	if err != nil || exists {
+		return err
+	}
+	return tx.Store.StoreAccount(&accountv1.Account{
+		Address: created.Address,
+		PubKey:  created.PubKey,
+	})
+}
+
+// HandleCreateAccount stores a new account. Accounts are created by an
+// existing one, which pays the transaction's fee, so creating accounts costs
+// the same as any other transaction. The new account starts without a
+// balance or any nonce used.
+func (a *AccountService) HandleCreateAccount(ctx context.Context, tx *module.Tx) error {
+	account := tx.Body().GetCreateAccount().GetAccount()
+	if account == nil || account.Address == "" {
+		return errors.New("create account requires an account address")
+	}
+	if _, err := newAccountKey(account); err != nil {
+		return err
+	}
+
+	exists, err := tx.Store.HasAccount(account.Address)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("account %s already exists", account.Address)
+	}
+	if account.Balance != 0 {
+		return errors.New("new accounts cannot carry a balance")
+	}
+	account.Nonce = 0
+
+	if err := tx.Store.StoreAccount(account); err != nil {
+		return err
+	}
+	a.Logger.Infow("created account", "address", account.Address)
+
+	return tx.EmitEvent(&chainv1.TransactionEvent{
+		Event: &chainv1.TransactionEvent_CreateAccount{
+			CreateAccount: &chainv1.CreateAccountEvent{
+				Address:     account.Address,
+				Sender:      tx.Header().Sender,
+				TxHash:      tx.HashHex(),
+				BlockHeight: uint64(tx.Height),
+			},
+		},
+	})
 }
@@ -2,24 +2,29 @@ package chain
 
 import (
 	"context"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"time"
 
 	"connectrpc.com/connect"
 	abcitypes "github.com/cometbft/cometbft/abci/types"
-	"github.com/cometbft/cometbft/libs/bytes"
 	"github.com/cometbft/cometbft/node"
 	"github.com/cometbft/cometbft/rpc/client/local"
-	"github.com/cosmos/gogoproto/proto"
 	"github.com/sonata-labs/sonata/config"
 	v1 "github.com/sonata-labs/sonata/gen/api/v1"
 	"github.com/sonata-labs/sonata/gen/api/v1/v1connect"
 	chainv1 "github.com/sonata-labs/sonata/gen/chain/v1"
+	"github.com/sonata-labs/sonata/store/chainstore"
 	"github.com/sonata-labs/sonata/types/module"
 	"go.uber.org/zap"
 )
 
 var _ v1connect.ChainHandler = (*ChainService)(nil)
 
+// UpgradeGas is the base gas for scheduling or cancelling an upgrade.
+const UpgradeGas uint64 = 10000
+
 type ChainService struct {
 	*module.BaseModule
 
@@ -58,7 +63,10 @@ func (c *ChainService) GetTransaction(ctx context.Context, req *connect.Request[
 		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("tx hash is required"))
 	}
 
-	txHash := bytes.HexBytes(req.Msg.TxHash)
+	txHash, err := hex.DecodeString(req.Msg.TxHash)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("tx hash is not hex: %w", err))
+	}
 
 	res, err := c.rpc.Tx(ctx, txHash, req.Msg.Prove)
 	if err != nil {
@@ -93,36 +101,230 @@ func (c *ChainService) SendTransaction(ctx context.Context, req *connect.Request
 	}), nil
 }
 
-func NewChainService(config *config.Config, logger *zap.Logger, node *node.Node) *ChainService {
-	svc := &ChainService{config: config, node: node, rpc: local.New(node)}
+func NewChainService(config *config.Config, logger *zap.Logger) *ChainService {
+	svc := &ChainService{config: config}
 	svc.BaseModule = module.NewBaseModule(logger.Named(svc.Name()))
 	return svc
 }
 
-// ABCI++ Callbacks
+// SetNode sets the CometBFT node the chain RPCs read from. The node is
+// created after the chain service, since creating it may already execute
+// blocks.
+func (c *ChainService) SetNode(node *node.Node) {
+	c.node = node
+	c.rpc = local.New(node)
+}
 
-func (c *ChainService) InitChain(ctx context.Context, req *abcitypes.InitChainRequest) (*abcitypes.InitChainResponse, error) {
-	c.Logger.Info("initializing chain")
-	return &abcitypes.InitChainResponse{}, nil
+// CatchingUp reports whether the node is still catching up with the
+// network.
+func (c *ChainService) CatchingUp(ctx context.Context) (bool, error) {
+	if c.rpc == nil {
+		return false, errors.New("node not set")
+	}
+	status, err := c.rpc.Status(ctx)
+	if err != nil {
+		return false, err
+	}
+	return status.SyncInfo.CatchingUp, nil
+}
+
+// Transaction Handlers
+
+// RegisterMsgHandlers registers the chain level checks every transaction must
+// pass and the upgrade transactions.
+func (c *ChainService) RegisterMsgHandlers(router module.MsgRouter) {
+	router.RegisterAnteHandler(c.VerifyChainID)
+	router.RegisterProposalCheck(c.VerifyChainID)
+	router.RegisterAnteHandler(c.VerifyTimeout)
+	router.RegisterMsgHandler((*chainv1.TransactionBody_ScheduleUpgrade)(nil), UpgradeGas, c.HandleScheduleUpgrade)
+	router.RegisterMsgHandler((*chainv1.TransactionBody_CancelUpgrade)(nil), UpgradeGas, c.HandleCancelUpgrade)
+}
+
+// VerifyChainID rejects transactions signed for another chain.
+func (c *ChainService) VerifyChainID(ctx context.Context, tx *module.Tx) error {
+	if chainID := tx.Header().GetChainId(); chainID != c.config.Sonata.ChainID {
+		return fmt.Errorf("wrong chain id %q, expected %q", chainID, c.config.Sonata.ChainID)
+	}
+	return nil
+}
+
+// VerifyTimeout rejects transactions whose timeout, in unix seconds, has
+// passed. Blocks compare against the block time so every validator agrees;
+// the mempool compares against the local clock and evicts expired
+// transactions when it is rechecked after each commit.
+func (c *ChainService) VerifyTimeout(ctx context.Context, tx *module.Tx) error {
+	timeout := tx.Header().GetTimeout()
+	if timeout == 0 {
+		return fmt.Errorf("transaction has no timeout")
+	}
+	if expiry := time.Unix(int64(timeout), 0); tx.Time.After(expiry) {
+		return fmt.Errorf("transaction expired at %s", expiry.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+// requireUpgradeAuthority rejects upgrade transactions not sent by the
+// upgrade authority in the chain params.
+func requireUpgradeAuthority(tx *module.Tx) error {
+	params, err := tx.Store.GetParams()
+	if err != nil {
+		return err
+	}
+	if params.UpgradeAuthority == "" {
+		return errors.New("upgrades are disabled, no upgrade authority is set")
+	}
+	if tx.Header().Sender != params.UpgradeAuthority {
+		return fmt.Errorf("only %s may schedule upgrades", params.UpgradeAuthority)
+	}
+	return nil
+}
+
+// HandleScheduleUpgrade schedules an upgrade at a future height, replacing
+// any upgrade already scheduled.
+func (c *ChainService) HandleScheduleUpgrade(ctx context.Context, tx *module.Tx) error {
+	if err := requireUpgradeAuthority(tx); err != nil {
+		return err
+	}
+
+	plan := tx.Body().GetScheduleUpgrade().GetPlan()
+	if plan.GetName() == "" {
+		return errors.New("upgrade plan requires a name")
+	}
+	if plan.Height <= tx.Height {
+		return fmt.Errorf("upgrade height %d is not after the current height %d", plan.Height, tx.Height)
+	}
+	if height, done, err := tx.Store.GetUpgradeDone(plan.Name); err != nil {
+		return err
+	} else if done {
+		return fmt.Errorf("upgrade %s was already applied at height %d", plan.Name, height)
+	}
+
+	if err := tx.Store.StoreUpgradePlan(plan); err != nil {
+		return err
+	}
+	c.Logger.Infow("scheduled upgrade", "upgrade", plan.Name, "height", plan.Height)
+
+	return tx.EmitEvent(&chainv1.TransactionEvent{
+		Event: &chainv1.TransactionEvent_ScheduleUpgrade{
+			ScheduleUpgrade: &chainv1.ScheduleUpgradeEvent{
+				Name:        plan.Name,
+				Height:      plan.Height,
+				Sender:      tx.Header().Sender,
+				TxHash:      tx.HashHex(),
+				BlockHeight: uint64(tx.Height),
+			},
+		},
+	})
 }
 
-func (c *ChainService) CheckTx(ctx context.Context, req *abcitypes.CheckTxRequest) (*abcitypes.CheckTxResponse, error) {
-	tx := req.Tx
+// HandleCancelUpgrade removes the scheduled upgrade.
+func (c *ChainService) HandleCancelUpgrade(ctx context.Context, tx *module.Tx) error {
+	if err := requireUpgradeAuthority(tx); err != nil {
+		return err
+	}
 
-	res := &abcitypes.CheckTxResponse{
-		Code: 0,
+	plan, err := tx.Store.GetUpgradePlan()
+	if err != nil {
+		return err
+	}
+	if plan == nil {
+		return errors.New("no upgrade is scheduled")
 	}
+	if err := tx.Store.DeleteUpgradePlan(); err != nil {
+		return err
+	}
+	c.Logger.Infow("cancelled upgrade", "upgrade", plan.Name, "height", plan.Height)
+
+	return tx.EmitEvent(&chainv1.TransactionEvent{
+		Event: &chainv1.TransactionEvent_CancelUpgrade{
+			CancelUpgrade: &chainv1.CancelUpgradeEvent{
+				Name:        plan.Name,
+				Sender:      tx.Header().Sender,
+				TxHash:      tx.HashHex(),
+				BlockHeight: uint64(tx.Height),
+			},
+		},
+	})
+}
+
+// Queries
+
+// RegisterQueryHandlers registers the state queries served by the chain module.
+func (c *ChainService) RegisterQueryHandlers(router module.QueryRouter) {
+	router.RegisterQueryHandler("/upgrade/plan", c.QueryUpgradePlan)
+}
+
+// QueryUpgradePlan serves /upgrade/plan, the scheduled upgrade if any.
+func (c *ChainService) QueryUpgradePlan(ctx context.Context, store *chainstore.ChainStore, args []string) ([]byte, error) {
+	if len(args) != 0 {
+		return nil, errors.New("usage: /upgrade/plan")
+	}
+	return []byte(chainstore.UpgradePlanKey), nil
+}
+
+// Genesis
 
-	var signedTransaction chainv1.SignedTransaction
-	err := proto.Unmarshal(tx, &signedTransaction)
+// InitGenesis stores the genesis chain params, or the defaults if the
+// genesis has none, along with the scheduled and applied upgrades of an
+// exported chain.
+func InitGenesis(ctx context.Context, store *chainstore.ChainStore, state *chainv1.GenesisState) error {
+	params := state.Params
+	if params == nil {
+		params = chainstore.DefaultParams()
+	}
+	if err := store.StoreParams(params); err != nil {
+		return err
+	}
+
+	if plan := state.UpgradePlan; plan != nil {
+		if plan.Name == "" {
+			return errors.New("genesis upgrade plan has no name")
+		}
+		if err := store.StoreUpgradePlan(plan); err != nil {
+			return err
+		}
+	}
+	for _, upgrade := range state.UpgradesDone {
+		if upgrade.Name == "" {
+			return errors.New("genesis applied upgrade has no name")
+		}
+		if _, done, err := store.GetUpgradeDone(upgrade.Name); err != nil {
+			return err
+		} else if done {
+			return fmt.Errorf("duplicate genesis applied upgrade %s", upgrade.Name)
+		}
+		if err := store.StoreUpgradeDone(upgrade.Name, upgrade.Height); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExportGenesis exports the current chain params and the scheduled and
+// applied upgrades.
+func ExportGenesis(ctx context.Context, store *chainstore.ChainStore, state *chainv1.GenesisState) error {
+	params, err := store.GetParams()
+	if err != nil {
+		return err
+	}
+	state.Params = params
+
+	plan, err := store.GetUpgradePlan()
 	if err != nil {
-		res.Code = 1
-		res.Info = "tx not a signed transaction"
-		res.Log = err.Error()
-		return res, nil
+		return err
 	}
+	state.UpgradePlan = plan
+	return store.IterateUpgradesDone(func(name string, height int64) error {
+		state.UpgradesDone = append(state.UpgradesDone, &chainv1.UpgradeDone{Name: name, Height: height})
+		return nil
+	})
+}
+
+// ABCI++ Callbacks
 
-	return res, nil
+func (c *ChainService) InitChain(ctx context.Context, req *abcitypes.InitChainRequest) (*abcitypes.InitChainResponse, error) {
+	c.Logger.Info("initializing chain")
+	return &abcitypes.InitChainResponse{}, nil
 }
 
 func (c *ChainService) PrepareProposal(ctx context.Context, req *abcitypes.PrepareProposalRequest) (*abcitypes.PrepareProposalResponse, error) {
@@ -2,16 +2,29 @@ package ddex
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
 
 	abcitypes "github.com/cometbft/cometbft/abci/types"
 	"connectrpc.com/connect"
 	"github.com/sonata-labs/sonata/config"
 	v1 "github.com/sonata-labs/sonata/gen/api/v1"
 	"github.com/sonata-labs/sonata/gen/api/v1/v1connect"
+	chainv1 "github.com/sonata-labs/sonata/gen/chain/v1"
+	ddexv1 "github.com/sonata-labs/sonata/gen/ddex/v1"
+	"github.com/sonata-labs/sonata/store/chainstore"
 	"github.com/sonata-labs/sonata/types/module"
 	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
+// MessageGas is the base gas for storing a DDEX message, charged on top of
+// the per byte cost of the transaction.
+const MessageGas uint64 = 20000
+
 type DDEXService struct {
 	*module.BaseModule
 	config *config.Config
@@ -76,3 +89,298 @@ func (d *DDEXService) FinalizeBlock(ctx context.Context, req *abcitypes.Finalize
 	d.Logger.Info("finalizing block")
 	return &abcitypes.FinalizeBlockResponse{}, nil
 }
+
+// Transaction Handlers
+
+// messageKind is a DDEX message kind recorded on chain: the transaction body
+// carrying it, the event emitted when it is stored and the genesis state field
+// it is exported to.
+type messageKind struct {
+	kind    string
+	body    any
+	msg     func(body *chainv1.TransactionBody) proto.Message
+	event   protoreflect.Name
+	genesis genesisMessages
+}
+
+// messageKinds are every DDEX message kind recorded on chain, in the order
+// their genesis messages are imported.
+var messageKinds = []messageKind{
+	{
+		kind:    chainstore.DDEXNewRelease,
+		body:    (*chainv1.TransactionBody_NewRelease)(nil),
+		msg:     func(body *chainv1.TransactionBody) proto.Message { return body.GetNewRelease().GetMsg() },
+		event:   "new_release",
+		genesis: genesisField(func(s *chainv1.GenesisDDEXMessages) *[]*ddexv1.NewReleaseMessage { return &s.Releases }),
+	},
+	{
+		kind:    chainstore.DDEXCatalogList,
+		body:    (*chainv1.TransactionBody_CatalogList)(nil),
+		msg:     func(body *chainv1.TransactionBody) proto.Message { return body.GetCatalogList().GetMsg() },
+		event:   "catalog_list",
+		genesis: genesisField(func(s *chainv1.GenesisDDEXMessages) *[]*ddexv1.CatalogListMessage { return &s.CatalogLists }),
+	},
+	{
+		kind:    chainstore.DDEXPurgeRelease,
+		body:    (*chainv1.TransactionBody_PurgeRelease)(nil),
+		msg:     func(body *chainv1.TransactionBody) proto.Message { return body.GetPurgeRelease().GetMsg() },
+		event:   "purge_release",
+		genesis: genesisField(func(s *chainv1.GenesisDDEXMessages) *[]*ddexv1.PurgeReleaseMessage { return &s.PurgeReleases }),
+	},
+	{
+		kind:    chainstore.DDEXPie,
+		body:    (*chainv1.TransactionBody_Pie)(nil),
+		msg:     func(body *chainv1.TransactionBody) proto.Message { return body.GetPie().GetMsg() },
+		event:   "pie",
+		genesis: genesisField(func(s *chainv1.GenesisDDEXMessages) *[]*ddexv1.PieMessage { return &s.Pies }),
+	},
+	{
+		kind:    chainstore.DDEXPieRequest,
+		body:    (*chainv1.TransactionBody_PieRequest)(nil),
+		msg:     func(body *chainv1.TransactionBody) proto.Message { return body.GetPieRequest().GetMsg() },
+		event:   "pie_request",
+		genesis: genesisField(func(s *chainv1.GenesisDDEXMessages) *[]*ddexv1.PieRequestMessage { return &s.PieRequests }),
+	},
+	{
+		kind:    chainstore.DDEXMead,
+		body:    (*chainv1.TransactionBody_Mead)(nil),
+		msg:     func(body *chainv1.TransactionBody) proto.Message { return body.GetMead().GetMsg() },
+		event:   "mead",
+		genesis: genesisField(func(s *chainv1.GenesisDDEXMessages) *[]*ddexv1.MeadMessage { return &s.Meads }),
+	},
+}
+
+// RegisterMsgHandlers registers the transaction bodies owned by the ddex module.
+func (d *DDEXService) RegisterMsgHandlers(router module.MsgRouter) {
+	for _, k := range messageKinds {
+		router.RegisterMsgHandler(k.body, MessageGas, d.handleMessage(k))
+	}
+}
+
+// handleMessage returns the handler storing the DDEX messages of a kind and
+// emitting its event.
+func (d *DDEXService) handleMessage(k messageKind) module.MsgHandler {
+	return func(ctx context.Context, tx *module.Tx) error {
+		m, err := d.storeMessage(tx, k.kind, k.msg(tx.Body()))
+		if err != nil {
+			return err
+		}
+		return tx.EmitEvent(messageEvent(k.event, tx, m))
+	}
+}
+
+// messageEvent returns the event for a stored message, set on the named
+// TransactionEvent field. The events of every DDEX kind have the same fields.
+func messageEvent(field protoreflect.Name, tx *module.Tx, m *storedMessage) *chainv1.TransactionEvent {
+	event := &chainv1.TransactionEvent{}
+	e := event.ProtoReflect().Mutable(event.ProtoReflect().Descriptor().Fields().ByName(field)).Message()
+	fields := e.Descriptor().Fields()
+	for name, value := range map[protoreflect.Name]string{
+		"address":           tx.Header().Sender,
+		"sender":            m.sender,
+		"message_id":        m.messageID,
+		"message_thread_id": m.threadID,
+		"version":           m.version,
+		"tx_hash":           tx.HashHex(),
+	} {
+		e.Set(fields.ByName(name), protoreflect.ValueOfString(value))
+	}
+	e.Set(fields.ByName("block_height"), protoreflect.ValueOfUint64(uint64(tx.Height)))
+	return event
+}
+
+// Genesis
+
+// genesisMessages are the accessors of the genesis field holding the messages
+// of a DDEX kind submitted by one account.
+type genesisMessages struct {
+	list   func(msgs *chainv1.GenesisDDEXMessages) []proto.Message
+	newMsg func() proto.Message
+	add    func(msgs *chainv1.GenesisDDEXMessages, msg proto.Message)
+}
+
+// genesisField declares the genesis field holding the messages of a kind.
+func genesisField[M proto.Message](field func(msgs *chainv1.GenesisDDEXMessages) *[]M) genesisMessages {
+	return genesisMessages{
+		list: func(msgs *chainv1.GenesisDDEXMessages) []proto.Message {
+			list := make([]proto.Message, len(*field(msgs)))
+			for i, msg := range *field(msgs) {
+				list[i] = msg
+			}
+			return list
+		},
+		newMsg: func() proto.Message {
+			var msg M
+			return msg.ProtoReflect().New().Interface()
+		},
+		add: func(msgs *chainv1.GenesisDDEXMessages, msg proto.Message) {
+			*field(msgs) = append(*field(msgs), msg.(M))
+		},
+	}
+}
+
+// InitGenesis imports the genesis DDEX messages of every account, keyed by
+// the account and the message id in their header as if each had been
+// submitted in a transaction. The accounts must be in the genesis state.
+func InitGenesis(ctx context.Context, store *chainstore.ChainStore, state *chainv1.GenesisState) error {
+	seen := make(map[string]bool)
+	for _, msgs := range state.Ddex {
+		if seen[msgs.Address] {
+			return fmt.Errorf("duplicate genesis ddex messages of %s", msgs.Address)
+		}
+		seen[msgs.Address] = true
+		exists, err := store.HasAccount(msgs.Address)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("genesis ddex messages of %s: no such account", msgs.Address)
+		}
+
+		for _, k := range messageKinds {
+			for i, msg := range k.genesis.list(msgs) {
+				version, header, err := messageHeader(msg)
+				if err != nil {
+					return fmt.Errorf("genesis %s %d of %s: %w", k.kind, i, msgs.Address, err)
+				}
+				id := chainstore.DDEXMessageID{Address: msgs.Address, MessageID: headerString(header, "message_id")}
+				if id.MessageID == "" {
+					return fmt.Errorf("genesis %s %d of %s: %s message header missing message id", k.kind, i, msgs.Address, version)
+				}
+				exists, err := store.HasDDEXMessage(k.kind, id)
+				if err != nil {
+					return err
+				}
+				if exists {
+					return fmt.Errorf("duplicate genesis %s message %s of %s", k.kind, id.MessageID, msgs.Address)
+				}
+				if err := store.StoreDDEXMessage(k.kind, id, msg); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// ExportGenesis exports the DDEX messages of every account in address order,
+// and those of each kind in message id order.
+func ExportGenesis(ctx context.Context, store *chainstore.ChainStore, state *chainv1.GenesisState) error {
+	byAddress := make(map[string]*chainv1.GenesisDDEXMessages)
+	for _, k := range messageKinds {
+		err := store.IterateDDEXMessages(k.kind, k.genesis.newMsg, func(id chainstore.DDEXMessageID, msg proto.Message) error {
+			msgs, ok := byAddress[id.Address]
+			if !ok {
+				msgs = &chainv1.GenesisDDEXMessages{Address: id.Address}
+				byAddress[id.Address] = msgs
+			}
+			k.genesis.add(msgs, msg)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("exporting %s messages: %w", k.kind, err)
+		}
+	}
+	for _, address := range slices.Sorted(maps.Keys(byAddress)) {
+		state.Ddex = append(state.Ddex, byAddress[address])
+	}
+	return nil
+}
+
+// Queries
+
+// RegisterQueryHandlers registers the state queries served by the ddex module.
+func (d *DDEXService) RegisterQueryHandlers(router module.QueryRouter) {
+	router.RegisterQueryHandler("/ddex/release", d.QueryRelease)
+}
+
+// QueryRelease serves /ddex/release/{address}/{message_id} for
+// NewReleaseMessages.
+func (d *DDEXService) QueryRelease(ctx context.Context, store *chainstore.ChainStore, args []string) ([]byte, error) {
+	if len(args) != 2 || args[0] == "" || args[1] == "" {
+		return nil, errors.New("usage: /ddex/release/{address}/{message_id}")
+	}
+	return chainstore.DDEXMessageKey(chainstore.DDEXNewRelease, chainstore.DDEXMessageID{Address: args[0], MessageID: args[1]}), nil
+}
+
+// storedMessage identifies a stored DDEX message by its MessageHeader.
+type storedMessage struct {
+	version   string
+	messageID string
+	threadID  string
+
+	// sender is the DDEX party id of the MessageSender, if set
+	sender string
+}
+
+// storeMessage records a DDEX message under the sending account and its
+// MessageId. DDEX has a sender never reuse a MessageId, corrections and
+// updates being new messages in the same thread, so resubmitting an id is
+// rejected rather than overwriting the message on record.
+func (d *DDEXService) storeMessage(tx *module.Tx, kind string, msg proto.Message) (*storedMessage, error) {
+	if !msg.ProtoReflect().IsValid() {
+		return nil, fmt.Errorf("%s transaction missing message", kind)
+	}
+
+	version, header, err := messageHeader(msg)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", kind, err)
+	}
+	m := &storedMessage{
+		version:   version,
+		messageID: headerString(header, "message_id"),
+		threadID:  headerString(header, "message_thread_id"),
+	}
+	if fd := header.Descriptor().Fields().ByName("message_sender"); fd != nil && header.Has(fd) {
+		m.sender = headerString(header.Get(fd).Message(), "party_id")
+	}
+	if m.messageID == "" {
+		return nil, fmt.Errorf("%s %s message header missing message id", kind, version)
+	}
+
+	id := chainstore.DDEXMessageID{Address: tx.Header().Sender, MessageID: m.messageID}
+	exists, err := tx.Store.HasDDEXMessage(kind, id)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, fmt.Errorf("%s message %s already exists", kind, m.messageID)
+	}
+
+	if err := tx.Store.StoreDDEXMessage(kind, id, msg); err != nil {
+		return nil, err
+	}
+	d.Logger.Infow("stored ddex message", "kind", kind, "version", version, "message_id", m.messageID)
+	return m, nil
+}
+
+// headerString returns a string field of a DDEX header message, or "" if the
+// version has no such field.
+func headerString(m protoreflect.Message, name protoreflect.Name) string {
+	fd := m.Descriptor().Fields().ByName(name)
+	if fd == nil || fd.IsList() || fd.Kind() != protoreflect.StringKind {
+		return ""
+	}
+	return m.Get(fd).String()
+}
+
+// messageHeader returns the version and MessageHeader of whichever DDEX
+// standard version is set on a versioned message wrapper.
+func messageHeader(msg proto.Message) (string, protoreflect.Message, error) {
+	m := msg.ProtoReflect()
+	oneof := m.Descriptor().Oneofs().ByName("msg")
+	if oneof == nil {
+		return "", nil, fmt.Errorf("%s is not a versioned ddex message", m.Descriptor().FullName())
+	}
+	field := m.WhichOneof(oneof)
+	if field == nil {
+		return "", nil, errors.New("no ddex version set")
+	}
+
+	versioned := m.Get(field).Message()
+	headerField := versioned.Descriptor().Fields().ByName("message_header")
+	if headerField == nil || !versioned.Has(headerField) {
+		return "", nil, fmt.Errorf("%s message missing message header", field.Name())
+	}
+	return string(field.Name()), versioned.Get(headerField).Message(), nil
+}
@@ -0,0 +1,124 @@
+package ddex
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/sonata-labs/sonata/config"
+	chainv1 "github.com/sonata-labs/sonata/gen/chain/v1"
+	ddexv1 "github.com/sonata-labs/sonata/gen/ddex/v1"
+	"github.com/sonata-labs/sonata/store/chainstore"
+	"github.com/sonata-labs/sonata/types/module"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestMessageEvent(t *testing.T) {
+	tx := &module.Tx{
+		Hash:   []byte{0xab, 0xcd},
+		Height: 42,
+		Signed: &chainv1.SignedTransaction{Transaction: &chainv1.Transaction{
+			Header: &chainv1.TransactionHeader{Sender: "sender-address"},
+		}},
+	}
+	m := &storedMessage{version: "v43", messageID: "msg-1", threadID: "thread-1", sender: "PADPIDA1"}
+
+	tests := []struct {
+		kind string
+		want *chainv1.TransactionEvent
+	}{
+		{"new_release", &chainv1.TransactionEvent{Event: &chainv1.TransactionEvent_NewRelease{NewRelease: &chainv1.NewReleaseEvent{
+			Address: "sender-address", Sender: "PADPIDA1", MessageId: "msg-1", MessageThreadId: "thread-1", Version: "v43", TxHash: "ABCD", BlockHeight: 42,
+		}}}},
+		{"catalog_list", &chainv1.TransactionEvent{Event: &chainv1.TransactionEvent_CatalogList{CatalogList: &chainv1.CatalogListEvent{
+			Address: "sender-address", Sender: "PADPIDA1", MessageId: "msg-1", MessageThreadId: "thread-1", Version: "v43", TxHash: "ABCD", BlockHeight: 42,
+		}}}},
+		{"purge_release", &chainv1.TransactionEvent{Event: &chainv1.TransactionEvent_PurgeRelease{PurgeRelease: &chainv1.PurgeReleaseEvent{
+			Address: "sender-address", Sender: "PADPIDA1", MessageId: "msg-1", MessageThreadId: "thread-1", Version: "v43", TxHash: "ABCD", BlockHeight: 42,
+		}}}},
+		{"pie", &chainv1.TransactionEvent{Event: &chainv1.TransactionEvent_Pie{Pie: &chainv1.PieEvent{
+			Address: "sender-address", Sender: "PADPIDA1", MessageId: "msg-1", MessageThreadId: "thread-1", Version: "v43", TxHash: "ABCD", BlockHeight: 42,
+		}}}},
+		{"pie_request", &chainv1.TransactionEvent{Event: &chainv1.TransactionEvent_PieRequest{PieRequest: &chainv1.PieRequestEvent{
+			Address: "sender-address", Sender: "PADPIDA1", MessageId: "msg-1", MessageThreadId: "thread-1", Version: "v43", TxHash: "ABCD", BlockHeight: 42,
+		}}}},
+		{"mead", &chainv1.TransactionEvent{Event: &chainv1.TransactionEvent_Mead{Mead: &chainv1.MeadEvent{
+			Address: "sender-address", Sender: "PADPIDA1", MessageId: "msg-1", MessageThreadId: "thread-1", Version: "v43", TxHash: "ABCD", BlockHeight: 42,
+		}}}},
+	}
+	if len(tests) != len(messageKinds) {
+		t.Fatalf("%d message kinds, %d tested", len(messageKinds), len(tests))
+	}
+	for i, tt := range tests {
+		t.Run(tt.kind, func(t *testing.T) {
+			k := messageKinds[i]
+			if string(k.event) != tt.kind {
+				t.Fatalf("message kind %d emits %s, want %s", i, k.event, tt.kind)
+			}
+			if got := messageEvent(k.event, tx, m); !proto.Equal(got, tt.want) {
+				t.Errorf("messageEvent = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStoreMessageScopedBySender(t *testing.T) {
+	store, err := chainstore.NewChainStore(filepath.Join(t.TempDir(), "chainstore"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	batch := store.Batch()
+	d := NewDDEXService(config.DefaultConfig(), zap.NewNop())
+
+	release := func(messageID string) *ddexv1.NewReleaseMessage {
+		msg := &ddexv1.NewReleaseMessage{}
+		if err := protojson.Unmarshal([]byte(`{"v43": {"messageHeader": {"messageId": "`+messageID+`"}}}`), msg); err != nil {
+			t.Fatal(err)
+		}
+		return msg
+	}
+	submit := func(sender, messageID string) error {
+		tx := &module.Tx{Store: batch, Signed: &chainv1.SignedTransaction{Transaction: &chainv1.Transaction{
+			Header: &chainv1.TransactionHeader{Sender: sender},
+		}}}
+		_, err := d.storeMessage(tx, chainstore.DDEXNewRelease, release(messageID))
+		return err
+	}
+
+	tests := []struct {
+		name      string
+		sender    string
+		messageID string
+		wantErr   bool
+	}{
+		{"first message", "ALICE", "msg-1", false},
+		{"same id from another sender", "BOB", "msg-1", false},
+		{"resubmitted id", "ALICE", "msg-1", true},
+		{"new id", "ALICE", "msg-2", false},
+	}
+	for _, tt := range tests {
+		if err := submit(tt.sender, tt.messageID); (err != nil) != tt.wantErr {
+			t.Errorf("%s: got %v, want error %v", tt.name, err, tt.wantErr)
+		}
+	}
+
+	var got []chainstore.DDEXMessageID
+	if err := batch.IterateDDEXMessages(chainstore.DDEXNewRelease, func() proto.Message { return &ddexv1.NewReleaseMessage{} },
+		func(id chainstore.DDEXMessageID, _ proto.Message) error {
+			got = append(got, id)
+			return nil
+		}); err != nil {
+		t.Fatal(err)
+	}
+	want := []chainstore.DDEXMessageID{
+		{Address: "ALICE", MessageID: "msg-1"},
+		{Address: "ALICE", MessageID: "msg-2"},
+		{Address: "BOB", MessageID: "msg-1"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("stored %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,37 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"connectrpc.com/connect"
+)
+
+// recoverHandler serves h for a module, turning a panic in it into a Connect
+// error naming the module instead of taking down the server.
+func (s *Server) recoverHandler(module string, h http.Handler) http.Handler {
+	errWriter := connect.NewErrorWriter()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+			s.Logger.Errorw("rpc panicked", "module", module, "path", r.URL.Path, "panic", rec, "stack", string(debug.Stack()))
+
+			code := connect.CodeInternal
+			if rec == "unimplemented" {
+				code = connect.CodeUnimplemented
+			}
+			err := connect.NewError(code, fmt.Errorf("module %s panicked: %v", module, rec))
+			if writeErr := errWriter.Write(w, r, err); writeErr != nil {
+				s.Logger.Errorw("failed to write rpc error", "module", module, "error", writeErr)
+			}
+		}()
+		h.ServeHTTP(w, r)
+	})
+}
@@ -8,7 +8,6 @@ import (
 
 	"github.com/labstack/echo/v4"
 	"github.com/sonata-labs/sonata/config"
-	"github.com/sonata-labs/sonata/gen/api/v1/v1connect"
 	"github.com/sonata-labs/sonata/types/module"
 	"go.uber.org/zap"
 )
@@ -19,14 +18,13 @@ type Server struct {
 
 	httpServer *echo.Echo
 
-	chain       v1connect.ChainHandler
-	storage     v1connect.StorageHandler
-	system      v1connect.SystemHandler
-	p2p         v1connect.P2PHandler
-	ddex        v1connect.DDEXHandler
-	composition v1connect.CompositionHandler
-	account     v1connect.AccountHandler
-	validator   v1connect.ValidatorHandler
+	// Connect services registered by modules
+	handlers []handler
+}
+
+type handler struct {
+	path    string
+	handler http.Handler
 }
 
 func (s *Server) Name() string {
@@ -35,25 +33,25 @@ func (s *Server) Name() string {
 
 var _ module.Module = (*Server)(nil)
 
-func NewServer(config *config.Config, logger *zap.Logger, chain v1connect.ChainHandler, storage v1connect.StorageHandler, system v1connect.SystemHandler, p2p v1connect.P2PHandler, ddex v1connect.DDEXHandler, composition v1connect.CompositionHandler, account v1connect.AccountHandler, validator v1connect.ValidatorHandler) (*Server, error) {
+func NewServer(config *config.Config, logger *zap.Logger) (*Server, error) {
 	httpServer := echo.New()
 
 	svc := &Server{
-		config:      config,
-		httpServer:  httpServer,
-		chain:       chain,
-		storage:     storage,
-		system:      system,
-		p2p:         p2p,
-		ddex:        ddex,
-		composition: composition,
-		account:     account,
-		validator:   validator,
+		config:     config,
+		httpServer: httpServer,
 	}
 	svc.BaseModule = module.NewBaseModule(logger.Named(svc.Name()))
 	return svc, nil
 }
 
+// RegisterHandler serves a Connect service of the named module under path,
+// as returned with the handler by its generated New*Handler constructor. A
+// panic in the handler is returned to the caller as an error. Handlers must
+// be registered before the server starts.
+func (s *Server) RegisterHandler(module, path string, h http.Handler) {
+	s.handlers = append(s.handlers, handler{path: path, handler: s.recoverHandler(module, h)})
+}
+
 func (s *Server) Start() error {
 	s.AwaitStartupDeps()
 	s.Logger.Info("starting")
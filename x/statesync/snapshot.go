@@ -0,0 +1,252 @@
+package statesync
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	abcitypes "github.com/cometbft/cometbft/abci/types"
+	"github.com/sonata-labs/sonata/store/chainstore"
+)
+
+// SnapshotFormat is the format of the snapshots this binary writes and
+// restores. Chunks hold the state keys and values in key order, each length
+// prefixed, and the metadata holds the SHA-256 hash of every chunk. The
+// snapshot hash is the hash of the metadata.
+const SnapshotFormat uint32 = 1
+
+// snapshotChunkSize is the size chunks are filled up to. An entry larger than
+// that gets a chunk of its own.
+const snapshotChunkSize = 4 << 20
+
+const (
+	snapshotFile      = "snapshot"
+	snapshotTmpPrefix = "tmp-"
+)
+
+// snapshotStore keeps the snapshots of this node on disk, one directory per
+// height and format.
+type snapshotStore struct {
+	dir string
+}
+
+func (s *snapshotStore) path(height uint64, format uint32) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%020d", height), strconv.FormatUint(uint64(format), 10))
+}
+
+// create writes a snapshot of the exported state. The snapshot is written to
+// a temporary directory first, so a crash never leaves a partial snapshot
+// behind that would be served.
+func (s *snapshotStore) create(export *chainstore.StateExport) (*abcitypes.Snapshot, error) {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return nil, err
+	}
+	tmp, err := os.MkdirTemp(s.dir, snapshotTmpPrefix)
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+
+	var (
+		chunk    bytes.Buffer
+		metadata []byte
+		chunks   uint32
+	)
+	flush := func() error {
+		hash := sha256.Sum256(chunk.Bytes())
+		if err := os.WriteFile(filepath.Join(tmp, strconv.FormatUint(uint64(chunks), 10)), chunk.Bytes(), 0o644); err != nil {
+			return err
+		}
+		metadata = append(metadata, hash[:]...)
+		chunks++
+		chunk.Reset()
+		return nil
+	}
+
+	err = export.Iterate(func(key, value []byte) error {
+		chunk.Write(binary.AppendUvarint(nil, uint64(len(key))))
+		chunk.Write(key)
+		chunk.Write(binary.AppendUvarint(nil, uint64(len(value))))
+		chunk.Write(value)
+		if chunk.Len() >= snapshotChunkSize {
+			return flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("exporting state: %w", err)
+	}
+	if chunk.Len() > 0 || chunks == 0 {
+		if err := flush(); err != nil {
+			return nil, err
+		}
+	}
+
+	hash := sha256.Sum256(metadata)
+	snapshot := &abcitypes.Snapshot{
+		Height:   uint64(export.Height),
+		Format:   SnapshotFormat,
+		Chunks:   chunks,
+		Hash:     hash[:],
+		Metadata: metadata,
+	}
+	data, err := snapshot.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(tmp, snapshotFile), data, 0o644); err != nil {
+		return nil, err
+	}
+
+	path := s.path(snapshot.Height, snapshot.Format)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	if err := os.RemoveAll(path); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// list returns the snapshots on disk, newest first.
+func (s *snapshotStore) list() ([]*abcitypes.Snapshot, error) {
+	heights, err := s.heights()
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []*abcitypes.Snapshot
+	for _, height := range heights {
+		formats, err := os.ReadDir(filepath.Join(s.dir, height))
+		if err != nil {
+			return nil, err
+		}
+		for _, format := range formats {
+			data, err := os.ReadFile(filepath.Join(s.dir, height, format.Name(), snapshotFile))
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+			snapshot := &abcitypes.Snapshot{}
+			if err := snapshot.Unmarshal(data); err != nil {
+				return nil, fmt.Errorf("reading snapshot %s/%s: %w", height, format.Name(), err)
+			}
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	return snapshots, nil
+}
+
+// heights returns the names of the height directories, newest first.
+func (s *snapshotStore) heights() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var heights []string
+	for _, entry := range entries {
+		if entry.IsDir() && !strings.HasPrefix(entry.Name(), snapshotTmpPrefix) {
+			heights = append(heights, entry.Name())
+		}
+	}
+	// the names are zero padded, so they sort by height
+	sort.Sort(sort.Reverse(sort.StringSlice(heights)))
+	return heights, nil
+}
+
+// loadChunk returns a chunk of a snapshot on disk.
+func (s *snapshotStore) loadChunk(height uint64, format, chunk uint32) ([]byte, error) {
+	return os.ReadFile(filepath.Join(s.path(height, format), strconv.FormatUint(uint64(chunk), 10)))
+}
+
+// prune removes all but the keep most recent snapshot heights, along with
+// temporary directories left by a crash. keep 0 removes none.
+func (s *snapshotStore) prune(keep int) error {
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), snapshotTmpPrefix) {
+			if err := os.RemoveAll(filepath.Join(s.dir, entry.Name())); err != nil {
+				return err
+			}
+		}
+	}
+
+	if keep <= 0 {
+		return nil
+	}
+	heights, err := s.heights()
+	if err != nil {
+		return err
+	}
+	for i := keep; i < len(heights); i++ {
+		if err := os.RemoveAll(filepath.Join(s.dir, heights[i])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// chunkHashes returns the chunk hashes listed in the metadata of a snapshot,
+// after checking them against the snapshot hash.
+func chunkHashes(snapshot *abcitypes.Snapshot) ([][]byte, error) {
+	if snapshot.Chunks == 0 || len(snapshot.Metadata) != int(snapshot.Chunks)*sha256.Size {
+		return nil, fmt.Errorf("metadata lists %d bytes of hashes for %d chunks", len(snapshot.Metadata), snapshot.Chunks)
+	}
+	hash := sha256.Sum256(snapshot.Metadata)
+	if !bytes.Equal(hash[:], snapshot.Hash) {
+		return nil, fmt.Errorf("metadata hash %X does not match snapshot hash %X", hash[:], snapshot.Hash)
+	}
+
+	hashes := make([][]byte, snapshot.Chunks)
+	for i := range hashes {
+		hashes[i] = snapshot.Metadata[i*sha256.Size : (i+1)*sha256.Size]
+	}
+	return hashes, nil
+}
+
+// decodeChunk calls fn with every key and value in a chunk.
+func decodeChunk(chunk []byte, fn func(key, value []byte) error) error {
+	next := func() ([]byte, error) {
+		n, size := binary.Uvarint(chunk)
+		if size <= 0 || uint64(len(chunk)-size) < n {
+			return nil, fmt.Errorf("malformed snapshot chunk")
+		}
+		field := chunk[size : size+int(n)]
+		chunk = chunk[size+int(n):]
+		return field, nil
+	}
+
+	for len(chunk) > 0 {
+		key, err := next()
+		if err != nil {
+			return err
+		}
+		value, err := next()
+		if err != nil {
+			return err
+		}
+		if err := fn(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,243 @@
+package statesync
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	abcitypes "github.com/cometbft/cometbft/abci/types"
+	"github.com/sonata-labs/sonata/config"
+	storagev1 "github.com/sonata-labs/sonata/gen/storage/v1"
+	"github.com/sonata-labs/sonata/store/chainstore"
+	"go.uber.org/zap"
+)
+
+func encodeEntry(key, value []byte) []byte {
+	b := binary.AppendUvarint(nil, uint64(len(key)))
+	b = append(b, key...)
+	b = binary.AppendUvarint(b, uint64(len(value)))
+	return append(b, value...)
+}
+
+func TestChunkHashes(t *testing.T) {
+	chunk0 := sha256.Sum256([]byte("chunk 0"))
+	chunk1 := sha256.Sum256([]byte("chunk 1"))
+	metadata := append(chunk0[:], chunk1[:]...)
+	hash := sha256.Sum256(metadata)
+
+	tests := []struct {
+		name     string
+		snapshot *abcitypes.Snapshot
+		wantErr  bool
+	}{
+		{"valid", &abcitypes.Snapshot{Chunks: 2, Metadata: metadata, Hash: hash[:]}, false},
+		{"no chunks", &abcitypes.Snapshot{Chunks: 0, Metadata: nil, Hash: hash[:]}, true},
+		{"more chunks than hashes", &abcitypes.Snapshot{Chunks: 3, Metadata: metadata, Hash: hash[:]}, true},
+		{"truncated metadata", &abcitypes.Snapshot{Chunks: 2, Metadata: metadata[:len(metadata)-1], Hash: hash[:]}, true},
+		{"wrong snapshot hash", &abcitypes.Snapshot{Chunks: 2, Metadata: metadata, Hash: chunk0[:]}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hashes, err := chunkHashes(tt.snapshot)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(hashes) != 2 || !bytes.Equal(hashes[0], chunk0[:]) || !bytes.Equal(hashes[1], chunk1[:]) {
+				t.Errorf("chunk hashes %X, want %X and %X", hashes, chunk0, chunk1)
+			}
+		})
+	}
+}
+
+func TestDecodeChunk(t *testing.T) {
+	entries := append(encodeEntry([]byte("account/a"), []byte("1")), encodeEntry([]byte("account/b"), nil)...)
+
+	tests := []struct {
+		name    string
+		chunk   []byte
+		want    []string
+		wantErr bool
+	}{
+		{"empty", nil, nil, false},
+		{"entries", entries, []string{"account/a=1", "account/b="}, false},
+		{"key without value", encodeEntry([]byte("account/a"), nil)[:10], nil, true},
+		{"truncated value", entries[:len(entries)-1], nil, true},
+		{"length past the end", binary.AppendUvarint(nil, 1<<40), nil, true},
+		{"malformed length", []byte{0xff}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			err := decodeChunk(tt.chunk, func(key, value []byte) error {
+				got = append(got, fmt.Sprintf("%s=%s", key, value))
+				return nil
+			})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, decoded %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("decoded %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// committedStore returns a chain store with uploads committed at height 1,
+// and its app hash. Large file names spread the state over several chunks.
+func committedStore(t *testing.T, uploads int, nameSize int) (*chainstore.ChainStore, []byte) {
+	t.Helper()
+	store, err := chainstore.NewChainStore(filepath.Join(t.TempDir(), "source"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	batch := store.Batch()
+	if err := batch.StoreParams(chainstore.DefaultParams()); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < uploads; i++ {
+		err := batch.StoreUpload(&storagev1.FileUploadMessage{
+			TranscodedCid: fmt.Sprintf("transcoded-%d", i),
+			OriginalCid:   fmt.Sprintf("original-%d", i),
+			FileName:      strings.Repeat("x", nameSize),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	appHash, err := batch.ComputeAppHash(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := batch.StoreLastBlock(1, appHash); err != nil {
+		t.Fatal(err)
+	}
+	if err := batch.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	return store, appHash
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	tests := []struct {
+		name       string
+		uploads    int
+		nameSize   int
+		corrupt    bool // deliver a corrupted chunk before each good one
+		wrongHash  bool // offer the snapshot with another app hash
+		wantChunks uint32
+	}{
+		{name: "single chunk", uploads: 3, nameSize: 10, wantChunks: 1},
+		{name: "several chunks", uploads: 5, nameSize: snapshotChunkSize / 2, wantChunks: 3},
+		{name: "corrupted chunks are refetched", uploads: 5, nameSize: snapshotChunkSize / 2, corrupt: true, wantChunks: 3},
+		{name: "wrong app hash", uploads: 3, nameSize: 10, wrongHash: true, wantChunks: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, appHash := committedStore(t, tt.uploads, tt.nameSize)
+			export, err := source.ExportState()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer export.Close()
+			snapshots := &snapshotStore{dir: filepath.Join(t.TempDir(), "snapshots")}
+			snapshot, err := snapshots.create(export)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if snapshot.Chunks != tt.wantChunks {
+				t.Fatalf("snapshot has %d chunks, want %d", snapshot.Chunks, tt.wantChunks)
+			}
+			listed, err := snapshots.list()
+			if err != nil || len(listed) != 1 || !bytes.Equal(listed[0].Hash, snapshot.Hash) {
+				t.Fatalf("listed snapshots %v (%v), want the created one", listed, err)
+			}
+
+			target, err := chainstore.NewChainStore(filepath.Join(t.TempDir(), "target"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer target.Close()
+			cfg := config.DefaultConfig()
+			cfg.Sonata.Snapshot.Path = filepath.Join(t.TempDir(), "target-snapshots")
+			svc := NewStateSyncService(cfg, zap.NewNop(), target)
+
+			ctx := context.Background()
+			offerHash := appHash
+			if tt.wrongHash {
+				offerHash = bytes.Repeat([]byte{1}, len(appHash))
+			}
+			offer, err := svc.OfferSnapshot(ctx, &abcitypes.OfferSnapshotRequest{Snapshot: snapshot, AppHash: offerHash})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if offer.Result != abcitypes.OFFER_SNAPSHOT_RESULT_ACCEPT {
+				t.Fatalf("offer result %v", offer.Result)
+			}
+
+			var result abcitypes.ApplySnapshotChunkResult
+			for i := uint32(0); i < snapshot.Chunks; i++ {
+				chunk, err := snapshots.loadChunk(snapshot.Height, snapshot.Format, i)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if tt.corrupt {
+					bad := bytes.Clone(chunk)
+					bad[len(bad)-1] ^= 0xff
+					resp, err := svc.ApplySnapshotChunk(ctx, &abcitypes.ApplySnapshotChunkRequest{Index: i, Chunk: bad, Sender: "bad-peer"})
+					if err != nil {
+						t.Fatal(err)
+					}
+					if resp.Result != abcitypes.APPLY_SNAPSHOT_CHUNK_RESULT_RETRY || len(resp.RejectSenders) != 1 {
+						t.Fatalf("corrupted chunk %d: result %v, rejected %v", i, resp.Result, resp.RejectSenders)
+					}
+				}
+				resp, err := svc.ApplySnapshotChunk(ctx, &abcitypes.ApplySnapshotChunkRequest{Index: i, Chunk: chunk, Sender: "peer"})
+				if err != nil {
+					t.Fatal(err)
+				}
+				result = resp.Result
+			}
+
+			height, restoredHash, err := target.GetLastBlock()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wrongHash {
+				if result != abcitypes.APPLY_SNAPSHOT_CHUNK_RESULT_REJECT_SNAPSHOT || height != 0 {
+					t.Fatalf("snapshot with a wrong app hash: result %v, restored height %d", result, height)
+				}
+				return
+			}
+			if result != abcitypes.APPLY_SNAPSHOT_CHUNK_RESULT_ACCEPT {
+				t.Fatalf("last chunk result %v", result)
+			}
+			if height != 1 || !bytes.Equal(restoredHash, appHash) {
+				t.Errorf("restored height %d app hash %X, want 1 and %X", height, restoredHash, appHash)
+			}
+			for i := 0; i < tt.uploads; i++ {
+				if _, err := target.GetUpload(fmt.Sprintf("transcoded-%d", i)); err != nil {
+					t.Errorf("restored upload %d: %v", i, err)
+				}
+			}
+		})
+	}
+}
@@ -1,7 +1,12 @@
 package statesync
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sync"
 
 	abcitypes "github.com/cometbft/cometbft/abci/types"
 	"github.com/sonata-labs/sonata/config"
@@ -10,7 +15,9 @@ import (
 	"go.uber.org/zap"
 )
 
-// StateSyncService is a service that provides state sync functionality
+// StateSyncService is a service that provides state sync functionality. It
+// takes snapshots of the chain store every snapshot interval, serves them to
+// nodes joining the network, and restores a snapshot offered to a new node.
 type StateSyncService struct {
 	*module.BaseModule
 
@@ -18,6 +25,23 @@ type StateSyncService struct {
 	logger *zap.Logger
 
 	chainStore *chainstore.ChainStore
+	snapshots  *snapshotStore
+
+	mu sync.Mutex
+	// writing is set while a snapshot is written in the background
+	writing bool
+	wg      sync.WaitGroup
+	// restore is the snapshot being restored, from its offer to its last chunk
+	restore *restore
+}
+
+// restore tracks the chunks applied of an offered snapshot.
+type restore struct {
+	snapshot    *abcitypes.Snapshot
+	appHash     []byte
+	chunkHashes [][]byte
+	next        uint32
+	state       *chainstore.StateImport
 }
 
 func NewStateSyncService(config *config.Config, logger *zap.Logger, chainStore *chainstore.ChainStore) *StateSyncService {
@@ -25,6 +49,7 @@ func NewStateSyncService(config *config.Config, logger *zap.Logger, chainStore *
 		config:     config,
 		logger:     logger,
 		chainStore: chainStore,
+		snapshots:  &snapshotStore{dir: config.Sonata.Snapshot.Path},
 	}
 	svc.BaseModule = module.NewBaseModule(logger.Named(svc.Name()))
 	return svc
@@ -34,6 +59,15 @@ func (s *StateSyncService) Name() string {
 	return "statesync"
 }
 
+// Stop waits for a snapshot being written to finish.
+func (s *StateSyncService) Stop() error {
+	s.AwaitShutdownDeps()
+	s.Logger.Info("stopping")
+	s.wg.Wait()
+	s.MarkStopped()
+	return nil
+}
+
 // ABCI++ Callbacks
 
 func (s *StateSyncService) FinalizeBlock(ctx context.Context, req *abcitypes.FinalizeBlockRequest) (*abcitypes.FinalizeBlockResponse, error) {
@@ -46,22 +80,167 @@ func (s *StateSyncService) Commit(ctx context.Context, req *abcitypes.CommitRequ
 	return &abcitypes.CommitResponse{}, nil
 }
 
-func (s *StateSyncService) ListSnapshots(ctx context.Context, req *abcitypes.ListSnapshotsRequest) (*abcitypes.ListSnapshotsResponse, error) {
-	s.Logger.Info("listing snapshots")
-	return &abcitypes.ListSnapshotsResponse{}, nil
+// RegisterCommitHooks registers taking snapshots of committed blocks.
+func (s *StateSyncService) RegisterCommitHooks(router module.CommitHookRouter) {
+	router.RegisterCommitHook(s.Name(), s.snapshotCommitted)
 }
 
-func (s *StateSyncService) OfferSnapshot(ctx context.Context, req *abcitypes.OfferSnapshotRequest) (*abcitypes.OfferSnapshotResponse, error) {
-	s.Logger.Info("offering snapshot")
-	return &abcitypes.OfferSnapshotResponse{}, nil
+// snapshotCommitted starts a snapshot of the committed block every snapshot
+// interval. The state is read from a database snapshot taken before the next
+// block can commit and written in the background. A height is skipped if the
+// previous snapshot is still being written.
+func (s *StateSyncService) snapshotCommitted(ctx context.Context, block *module.CommittedBlock) error {
+	cfg := s.config.Sonata.Snapshot
+	if cfg.Interval <= 0 || block.Height%cfg.Interval != 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	if s.writing {
+		s.mu.Unlock()
+		return fmt.Errorf("skipping snapshot at height %d, the previous snapshot is still being written", block.Height)
+	}
+	s.writing = true
+	s.mu.Unlock()
+
+	export, err := s.chainStore.ExportState()
+	if err != nil {
+		s.doneWriting()
+		return fmt.Errorf("exporting state at height %d: %w", block.Height, err)
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer s.doneWriting()
+		defer export.Close()
+
+		snapshot, err := s.snapshots.create(export)
+		if err != nil {
+			s.Logger.Errorf("failed to write snapshot at height %d: %v", export.Height, err)
+			return
+		}
+		s.Logger.Infof("wrote snapshot at height %d in %d chunks", snapshot.Height, snapshot.Chunks)
+
+		if err := s.snapshots.prune(cfg.KeepRecent); err != nil {
+			s.Logger.Warnf("failed to prune snapshots: %v", err)
+		}
+	}()
+	return nil
+}
+
+func (s *StateSyncService) doneWriting() {
+	s.mu.Lock()
+	s.writing = false
+	s.mu.Unlock()
+}
+
+func (s *StateSyncService) ListSnapshots(ctx context.Context, req *abcitypes.ListSnapshotsRequest) (*abcitypes.ListSnapshotsResponse, error) {
+	snapshots, err := s.snapshots.list()
+	if err != nil {
+		return nil, fmt.Errorf("listing snapshots: %w", err)
+	}
+	return &abcitypes.ListSnapshotsResponse{Snapshots: snapshots}, nil
 }
 
 func (s *StateSyncService) LoadSnapshotChunk(ctx context.Context, req *abcitypes.LoadSnapshotChunkRequest) (*abcitypes.LoadSnapshotChunkResponse, error) {
-	s.Logger.Info("loading snapshot chunk")
-	return &abcitypes.LoadSnapshotChunkResponse{}, nil
+	chunk, err := s.snapshots.loadChunk(req.Height, req.Format, req.Chunk)
+	if err != nil {
+		// the snapshot may have been pruned since it was listed
+		s.Logger.Warnf("failed to load chunk %d of snapshot at height %d: %v", req.Chunk, req.Height, err)
+		return &abcitypes.LoadSnapshotChunkResponse{}, nil
+	}
+	return &abcitypes.LoadSnapshotChunkResponse{Chunk: chunk}, nil
+}
+
+// OfferSnapshot starts restoring a snapshot into the empty chain store of a
+// new node. The offer replaces a snapshot offered before whose restore did
+// not complete.
+func (s *StateSyncService) OfferSnapshot(ctx context.Context, req *abcitypes.OfferSnapshotRequest) (*abcitypes.OfferSnapshotResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.discardRestore()
+
+	snapshot := req.Snapshot
+	if snapshot == nil {
+		return &abcitypes.OfferSnapshotResponse{Result: abcitypes.OFFER_SNAPSHOT_RESULT_REJECT}, nil
+	}
+	if snapshot.Format != SnapshotFormat {
+		return &abcitypes.OfferSnapshotResponse{Result: abcitypes.OFFER_SNAPSHOT_RESULT_REJECT_FORMAT}, nil
+	}
+	hashes, err := chunkHashes(snapshot)
+	if err != nil {
+		s.Logger.Warnf("rejecting snapshot at height %d: %v", snapshot.Height, err)
+		return &abcitypes.OfferSnapshotResponse{Result: abcitypes.OFFER_SNAPSHOT_RESULT_REJECT}, nil
+	}
+
+	state, err := s.chainStore.ImportState(int64(snapshot.Height))
+	if err != nil {
+		return nil, fmt.Errorf("restoring snapshot at height %d: %w", snapshot.Height, err)
+	}
+	s.restore = &restore{snapshot: snapshot, appHash: req.AppHash, chunkHashes: hashes, state: state}
+	s.Logger.Infof("restoring snapshot at height %d from %d chunks", snapshot.Height, snapshot.Chunks)
+
+	return &abcitypes.OfferSnapshotResponse{Result: abcitypes.OFFER_SNAPSHOT_RESULT_ACCEPT}, nil
 }
 
+// ApplySnapshotChunk imports the next chunk of the offered snapshot. A chunk
+// that does not match its hash is fetched again from another peer. Once the
+// last chunk is imported, the state is committed if it hashes to the app hash
+// the snapshot's height was committed with.
 func (s *StateSyncService) ApplySnapshotChunk(ctx context.Context, req *abcitypes.ApplySnapshotChunkRequest) (*abcitypes.ApplySnapshotChunkResponse, error) {
-	s.Logger.Info("applying snapshot chunk")
-	return &abcitypes.ApplySnapshotChunkResponse{}, nil
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.restore
+	if r == nil {
+		return nil, errors.New("no snapshot offered")
+	}
+	switch {
+	case req.Index < r.next:
+		// already imported
+		return &abcitypes.ApplySnapshotChunkResponse{Result: abcitypes.APPLY_SNAPSHOT_CHUNK_RESULT_ACCEPT}, nil
+	case req.Index > r.next:
+		return nil, fmt.Errorf("chunk %d applied before chunk %d", req.Index, r.next)
+	}
+
+	hash := sha256.Sum256(req.Chunk)
+	if !bytes.Equal(hash[:], r.chunkHashes[req.Index]) {
+		s.Logger.Warnf("chunk %d of snapshot at height %d from %s does not match its hash", req.Index, r.snapshot.Height, req.Sender)
+		return &abcitypes.ApplySnapshotChunkResponse{
+			Result:        abcitypes.APPLY_SNAPSHOT_CHUNK_RESULT_RETRY,
+			RefetchChunks: []uint32{req.Index},
+			RejectSenders: []string{req.Sender},
+		}, nil
+	}
+
+	if err := decodeChunk(req.Chunk, r.state.Add); err != nil {
+		s.Logger.Warnf("rejecting snapshot at height %d: chunk %d: %v", r.snapshot.Height, req.Index, err)
+		s.discardRestore()
+		return &abcitypes.ApplySnapshotChunkResponse{Result: abcitypes.APPLY_SNAPSHOT_CHUNK_RESULT_REJECT_SNAPSHOT}, nil
+	}
+	r.next++
+	if r.next < r.snapshot.Chunks {
+		return &abcitypes.ApplySnapshotChunkResponse{Result: abcitypes.APPLY_SNAPSHOT_CHUNK_RESULT_ACCEPT}, nil
+	}
+
+	s.restore = nil
+	err := r.state.Commit(r.appHash)
+	if errors.Is(err, chainstore.ErrAppHashMismatch) {
+		s.Logger.Warnf("rejecting snapshot at height %d: %v", r.snapshot.Height, err)
+		return &abcitypes.ApplySnapshotChunkResponse{Result: abcitypes.APPLY_SNAPSHOT_CHUNK_RESULT_REJECT_SNAPSHOT}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("committing snapshot at height %d: %w", r.snapshot.Height, err)
+	}
+	s.Logger.Infof("restored state at height %d", r.snapshot.Height)
+
+	return &abcitypes.ApplySnapshotChunkResponse{Result: abcitypes.APPLY_SNAPSHOT_CHUNK_RESULT_ACCEPT}, nil
+}
+
+// discardRestore drops the snapshot being restored, if any. s.mu must be held.
+func (s *StateSyncService) discardRestore() {
+	if s.restore != nil {
+		s.restore.state.Discard()
+		s.restore = nil
+	}
 }
@@ -0,0 +1,263 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/sonata-labs/sonata/common/cid"
+	v1 "github.com/sonata-labs/sonata/gen/api/v1"
+	"github.com/sonata-labs/sonata/gen/api/v1/v1connect"
+	storagev1 "github.com/sonata-labs/sonata/gen/storage/v1"
+	"github.com/sonata-labs/sonata/sdk"
+	"github.com/sonata-labs/sonata/store/chainstore"
+)
+
+const (
+	// bootstrapRetryDelay is how long the bootstrap waits for the node to
+	// catch up, and between passes that left files unfetched.
+	bootstrapRetryDelay = 30 * time.Second
+
+	// bootstrapFetchTimeout bounds fetching a single file from a peer.
+	bootstrapFetchTimeout = 5 * time.Minute
+
+	// bootstrapLogEvery is the number of uploads between progress logs.
+	bootstrapLogEvery = 100
+)
+
+// syncStatus reports whether the node is still catching up with the network.
+// The chain service implements it.
+type syncStatus interface {
+	CatchingUp(ctx context.Context) (bool, error)
+}
+
+// bootstrapProgress counts the uploads handled by the current or last pass of
+// the bootstrap.
+type bootstrapProgress struct {
+	mu       sync.Mutex
+	running  bool
+	complete bool
+	total    uint64
+	present  uint64
+	fetched  uint64
+	failed   uint64
+	bytes    uint64
+}
+
+func (p *bootstrapProgress) update(fn func(p *bootstrapProgress)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fn(p)
+}
+
+// GetBootstrapProgress reports how far the node is in fetching the files it
+// serves from peers.
+func (s *StorageService) GetBootstrapProgress(ctx context.Context, req *connect.Request[v1.GetBootstrapProgressRequest]) (*connect.Response[v1.GetBootstrapProgressResponse], error) {
+	p := &s.bootstrap
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return connect.NewResponse(&v1.GetBootstrapProgressResponse{
+		Running:      p.running,
+		Complete:     p.complete,
+		Total:        p.total,
+		Present:      p.present,
+		Fetched:      p.fetched,
+		Failed:       p.failed,
+		FetchedBytes: p.bytes,
+	}), nil
+}
+
+// runBootstrap fetches the transcoded files this node is responsible for
+// that are missing from the local store, once the node has caught up with
+// the chain. Passes over the uploads on chain repeat until every file has
+// been fetched or ctx is cancelled.
+func (s *StorageService) runBootstrap(ctx context.Context) {
+	cfg := s.config.Sonata.Bootstrap
+	if len(cfg.Peers) == 0 {
+		s.Logger.Warn("bootstrap enabled without peers to fetch files from")
+		return
+	}
+	peers := make([]v1connect.StorageClient, len(cfg.Peers))
+	for i, url := range cfg.Peers {
+		peers[i] = sdk.NewSonataSDK(url).Storage
+	}
+
+	s.bootstrap.update(func(p *bootstrapProgress) { p.running = true })
+	defer s.bootstrap.update(func(p *bootstrapProgress) { p.running = false })
+
+	// the uploads on chain are only known once the node has caught up
+	for {
+		if status, ok := s.chain.(syncStatus); ok {
+			catchingUp, err := status.CatchingUp(ctx)
+			if err == nil && !catchingUp {
+				break
+			}
+		} else {
+			break
+		}
+		if !sleepCtx(ctx, bootstrapRetryDelay) {
+			return
+		}
+	}
+
+	for {
+		failed, err := s.bootstrapPass(ctx, peers)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			s.Logger.Warnf("bootstrap pass failed: %v", err)
+		} else if failed == 0 {
+			s.bootstrap.update(func(p *bootstrapProgress) { p.complete = true })
+			s.Logger.Info("bootstrap complete")
+			return
+		} else {
+			s.Logger.Warnf("bootstrap could not fetch %d files, retrying in %s", failed, bootstrapRetryDelay)
+		}
+		if !sleepCtx(ctx, bootstrapRetryDelay) {
+			return
+		}
+	}
+}
+
+// bootstrapPass fetches every missing file this node is responsible for and
+// returns the number of files no peer could provide.
+func (s *StorageService) bootstrapPass(ctx context.Context, peers []v1connect.StorageClient) (uint64, error) {
+	s.bootstrap.update(func(p *bootstrapProgress) {
+		p.total, p.present, p.fetched, p.failed, p.bytes = 0, 0, 0, 0, 0
+	})
+
+	var (
+		cursor []byte
+		failed uint64
+		seen   uint64
+	)
+	for {
+		uploads, next, err := s.chainStore.ListUploads("", cursor, chainstore.MaxPageLimit)
+		if err != nil {
+			return failed, fmt.Errorf("listing uploads: %w", err)
+		}
+
+		for _, upload := range uploads {
+			if ctx.Err() != nil {
+				return failed, ctx.Err()
+			}
+			if !s.responsibleFor(upload) {
+				continue
+			}
+			seen++
+
+			if s.localStore.HasTranscoded(upload.TranscodedCid) {
+				s.bootstrap.update(func(p *bootstrapProgress) { p.total++; p.present++ })
+			} else if size, err := s.fetchTranscoded(ctx, peers, upload); err != nil {
+				failed++
+				s.Logger.Warnf("failed to fetch %s: %v", upload.TranscodedCid, err)
+				s.bootstrap.update(func(p *bootstrapProgress) { p.total++; p.failed++ })
+			} else {
+				s.bootstrap.update(func(p *bootstrapProgress) { p.total++; p.fetched++; p.bytes += size })
+			}
+
+			if seen%bootstrapLogEvery == 0 {
+				s.logBootstrapProgress()
+			}
+		}
+
+		if next == nil {
+			break
+		}
+		cursor = next
+	}
+
+	s.logBootstrapProgress()
+	return failed, nil
+}
+
+func (s *StorageService) logBootstrapProgress() {
+	p := &s.bootstrap
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s.Logger.Infof("bootstrap: %d files, %d present, %d fetched (%d bytes), %d failed", p.total, p.present, p.fetched, p.bytes, p.failed)
+}
+
+// responsibleFor reports whether this node serves the transcoded file of an
+// upload: every upload if it replicates all of them, otherwise the uploads
+// its account transcoded.
+func (s *StorageService) responsibleFor(upload *storagev1.FileUploadMessage) bool {
+	if s.config.Sonata.Bootstrap.ReplicateAll {
+		return true
+	}
+	return upload.TranscoderAddress == s.accountAddress()
+}
+
+// fetchTranscoded fetches the transcoded file of an upload from the first
+// peer that serves it intact, and stores it in the local store.
+func (s *StorageService) fetchTranscoded(ctx context.Context, peers []v1connect.StorageClient, upload *storagev1.FileUploadMessage) (uint64, error) {
+	transcodedCID := upload.TranscodedCid
+	var lastErr error
+	for i, peer := range peers {
+		data, err := downloadFile(ctx, peer, transcodedCID, maxTranscodedSize(upload))
+		if err == nil {
+			err = cid.Validate(transcodedCID, data)
+		}
+		if err != nil {
+			lastErr = fmt.Errorf("peer %s: %w", s.config.Sonata.Bootstrap.Peers[i], err)
+			continue
+		}
+
+		if err := s.localStore.StoreTranscoded(transcodedCID, data); err != nil {
+			return 0, err
+		}
+		return uint64(len(data)), nil
+	}
+	return 0, lastErr
+}
+
+// maxTranscodedSize bounds the transcoded file of an upload a peer may send:
+// the upload's recorded size, or the largest direct upload for a small file
+// transcoded larger than its original.
+func maxTranscodedSize(upload *storagev1.FileUploadMessage) uint64 {
+	return max(upload.Size, MaxDirectUploadSize)
+}
+
+// downloadFile streams a file from a peer in chunks, so its size is not
+// limited by the largest message a peer accepts to send. The download is
+// abandoned as soon as the peer sends more than maxSize bytes.
+func downloadFile(ctx context.Context, peer v1connect.StorageClient, fileCID string, maxSize uint64) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, bootstrapFetchTimeout)
+	defer cancel()
+
+	stream, err := peer.DownloadFileChunk(ctx, connect.NewRequest(&v1.DownloadFileChunkRequest{Cid: fileCID}))
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	var data bytes.Buffer
+	for stream.Receive() {
+		chunk := stream.Msg().Data
+		if uint64(data.Len())+uint64(len(chunk)) > maxSize {
+			return nil, fmt.Errorf("file exceeds %d bytes", maxSize)
+		}
+		data.Write(chunk)
+	}
+	if err := stream.Err(); err != nil {
+		return nil, err
+	}
+	return data.Bytes(), nil
+}
+
+// sleepCtx waits for d, returning false if ctx is cancelled first.
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -3,12 +3,16 @@ package storage
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"connectrpc.com/connect"
+	"github.com/cockroachdb/pebble"
 	abcitypes "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/crypto"
 	"github.com/cosmos/gogoproto/proto"
 	"github.com/sonata-labs/sonata/common/cid"
 	"github.com/sonata-labs/sonata/config"
@@ -20,6 +24,7 @@ import (
 	"github.com/sonata-labs/sonata/store/chainstore"
 	"github.com/sonata-labs/sonata/store/localstore"
 	"github.com/sonata-labs/sonata/types/module"
+	"github.com/sonata-labs/sonata/types/signing"
 	"go.uber.org/zap"
 )
 
@@ -27,6 +32,9 @@ const (
 	MaxDirectUploadSize = 50 * 1024 * 1024 // 50MB
 	MaxChunkSize        = 10 * 1024 * 1024 // 10MB
 	MaxEncoderWorkers   = 4
+
+	// FileUploadGas is the base gas for recording a file upload
+	FileUploadGas uint64 = 20000
 )
 
 type StorageService struct {
@@ -36,6 +44,20 @@ type StorageService struct {
 	chainStore *chainstore.ChainStore
 	encoder    *media.MediaEncoder
 	chain      v1connect.ChainHandler
+
+	// signer is the node's key, used to sign the transactions this node
+	// submits on behalf of its transcoder
+	signer crypto.PrivKey
+
+	// txMu serializes submissions so each reads the nonce its predecessor
+	// committed
+	txMu sync.Mutex
+
+	// bootstrap fetches the files this node serves from peers in the
+	// background, until stopBootstrap is called
+	bootstrap     bootstrapProgress
+	stopBootstrap context.CancelFunc
+	bootstrapDone sync.WaitGroup
 }
 
 // SetChain sets the chain handler dependency (in-process, no network).
@@ -47,6 +69,39 @@ func (s *StorageService) Name() string {
 	return "storage"
 }
 
+// Start starts fetching the files this node serves from peers, if bootstrap
+// is enabled.
+func (s *StorageService) Start() error {
+	s.AwaitStartupDeps()
+	s.Logger.Info("starting")
+
+	if s.config.Sonata.Bootstrap.Enabled {
+		ctx, cancel := context.WithCancel(context.Background())
+		s.stopBootstrap = cancel
+		s.bootstrapDone.Add(1)
+		go func() {
+			defer s.bootstrapDone.Done()
+			s.runBootstrap(ctx)
+		}()
+	}
+
+	s.MarkReady()
+	return nil
+}
+
+// Stop stops a running bootstrap.
+func (s *StorageService) Stop() error {
+	s.AwaitShutdownDeps()
+	s.Logger.Info("stopping")
+
+	if s.stopBootstrap != nil {
+		s.stopBootstrap()
+		s.bootstrapDone.Wait()
+	}
+	s.MarkStopped()
+	return nil
+}
+
 func (s *StorageService) DownloadFile(ctx context.Context, req *connect.Request[v1.DownloadFileRequest]) (*connect.Response[v1.DownloadFileResponse], error) {
 	data, err := s.localStore.GetTranscoded(req.Msg.Cid)
 	if err != nil {
@@ -58,6 +113,17 @@ func (s *StorageService) DownloadFile(ctx context.Context, req *connect.Request[
 	}), nil
 }
 
+// ListUploads returns a page of the uploads recorded on chain in transcoded
+// CID order, optionally only those of one uploader.
+func (s *StorageService) ListUploads(ctx context.Context, req *connect.Request[v1.ListUploadsRequest]) (*connect.Response[v1.ListUploadsResponse], error) {
+	uploads, next, err := s.chainStore.ListUploads(req.Msg.UploaderAddress, req.Msg.Cursor, int(req.Msg.Limit))
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	return connect.NewResponse(&v1.ListUploadsResponse{Uploads: uploads, NextCursor: next}), nil
+}
+
 func (s *StorageService) DownloadFileChunk(ctx context.Context, req *connect.Request[v1.DownloadFileChunkRequest], stream *connect.ServerStream[v1.DownloadFileChunkResponse]) error {
 	data, err := s.localStore.GetTranscoded(req.Msg.Cid)
 	if err != nil {
@@ -273,9 +339,10 @@ func (s *StorageService) submitFileUploadTx(ctx context.Context, originalCID, tr
 		return fmt.Errorf("chain client not set")
 	}
 
+	transcoderAddr := s.accountAddress()
+
 	// Build the transaction
 	uploaderAddr := "" // TODO: Get from request context/auth
-	transcoderAddr := s.config.Sonata.ValidatorAddress
 
 	msg := &storagev1.FileUploadMessage{
 		UploaderAddress:   uploaderAddr,
@@ -287,28 +354,52 @@ func (s *StorageService) submitFileUploadTx(ctx context.Context, originalCID, tr
 		Size:              meta.Size,
 	}
 
-	signedTx := &chainv1.SignedTransaction{
-		Transaction: &chainv1.Transaction{
-			Header: &chainv1.TransactionHeader{
-				ChainId:   s.config.Sonata.ChainID,
-				Nonce:     uint64(time.Now().UnixNano()),
-				GasPrice:  1,
-				GasLimit:  100000,
-				Timeout:   uint64(time.Now().Add(time.Hour).Unix()),
-				Sender:    transcoderAddr,
-				Recipient: "",
-			},
-			Body: &chainv1.TransactionBody{
-				Body: &chainv1.TransactionBody_FileUpload{
-					FileUpload: &chainv1.FileUploadTransaction{
-						Msg: msg,
-					},
-				},
+	return s.sendTx(ctx, transcoderAddr, &chainv1.TransactionBody{
+		Body: &chainv1.TransactionBody_FileUpload{
+			FileUpload: &chainv1.FileUploadTransaction{
+				Msg: msg,
 			},
 		},
-		Signature: &chainv1.TransactionSignature{
-			Signature: []byte("validator-signature"), // TODO: Proper signing
+	})
+}
+
+// accountAddress returns the address of the account the node signs with,
+// which is bound to the node key.
+func (s *StorageService) accountAddress() string {
+	return s.signer.PubKey().Address().String()
+}
+
+// sendTx signs body as sender with the node key and submits it to the chain,
+// waiting for it to be committed. The node's account pays the fee, so it
+// must have been funded in genesis or created by a funded account.
+func (s *StorageService) sendTx(ctx context.Context, sender string, body *chainv1.TransactionBody) error {
+	s.txMu.Lock()
+	defer s.txMu.Unlock()
+
+	account, err := s.chainStore.GetAccount(sender)
+	if errors.Is(err, pebble.ErrNotFound) {
+		return fmt.Errorf("node account %s does not exist", sender)
+	} else if err != nil {
+		return err
+	}
+	nonce := account.Nonce + 1
+
+	tx := &chainv1.Transaction{
+		Header: &chainv1.TransactionHeader{
+			ChainId:   s.config.Sonata.ChainID,
+			Nonce:     nonce,
+			GasPrice:  1,
+			GasLimit:  100000,
+			Timeout:   uint64(time.Now().Add(time.Hour).Unix()),
+			Sender:    sender,
+			Recipient: "",
 		},
+		Body: body,
+	}
+
+	signedTx, err := signing.Sign(tx, s.signer)
+	if err != nil {
+		return err
 	}
 
 	txBytes, err := proto.Marshal(signedTx)
@@ -335,6 +426,7 @@ func NewStorageService(
 	logger *zap.Logger,
 	localStore *localstore.LocalStore,
 	chainStore *chainstore.ChainStore,
+	signer crypto.PrivKey,
 ) (*StorageService, error) {
 	encoder, err := media.NewMediaEncoder(MaxEncoderWorkers)
 	if err != nil {
@@ -346,6 +438,7 @@ func NewStorageService(
 		localStore: localStore,
 		chainStore: chainStore,
 		encoder:    encoder,
+		signer:     signer,
 	}
 	svc.BaseModule = module.NewBaseModule(logger.Named(svc.Name()))
 	return svc, nil
@@ -366,36 +459,139 @@ func (s *StorageService) ProcessProposal(ctx context.Context, req *abcitypes.Pro
 }
 
 func (s *StorageService) FinalizeBlock(ctx context.Context, req *abcitypes.FinalizeBlockRequest) (*abcitypes.FinalizeBlockResponse, error) {
-	for _, txBytes := range req.Txs {
-		var signedTx chainv1.SignedTransaction
-		if err := proto.Unmarshal(txBytes, &signedTx); err != nil {
+	return &abcitypes.FinalizeBlockResponse{}, nil
+}
+
+// Transaction Handlers
+
+// RegisterMsgHandlers registers the transaction bodies owned by the storage module.
+func (s *StorageService) RegisterMsgHandlers(router module.MsgRouter) {
+	router.RegisterMsgHandler((*chainv1.TransactionBody_FileUpload)(nil), FileUploadGas, s.HandleFileUpload)
+}
+
+func (s *StorageService) RegisterCommitHooks(router module.CommitHookRouter) {
+	router.RegisterCommitHook(s.Name(), s.deleteCommittedOriginals)
+}
+
+// deleteCommittedOriginals removes the original of every upload this node
+// transcoded that was committed in the block from the local store. It only
+// runs once the chain has recorded the transcoded upload, so a crash never
+// loses an original the chain does not know about.
+func (s *StorageService) deleteCommittedOriginals(ctx context.Context, block *module.CommittedBlock) error {
+	for _, event := range block.Events {
+		upload := event.GetFileUpload()
+		if upload == nil || upload.TranscoderAddress != s.accountAddress() {
 			continue
 		}
+		if err := s.localStore.DeleteUpload(upload.OriginalCid); err != nil {
+			s.Logger.Warnf("failed to delete original upload %s: %v", upload.OriginalCid, err)
+		}
+	}
+	return nil
+}
 
-		if signedTx.Transaction == nil || signedTx.Transaction.Body == nil {
-			continue
+// Genesis
+
+// InitGenesis imports the genesis uploads along with their original CID index.
+func InitGenesis(ctx context.Context, store *chainstore.ChainStore, state *chainv1.GenesisState) error {
+	for _, upload := range state.Uploads {
+		if upload.TranscodedCid == "" || upload.OriginalCid == "" {
+			return fmt.Errorf("genesis upload %q missing cid", upload.TranscodedCid)
 		}
+		_, err := store.GetUpload(upload.TranscodedCid)
+		if err == nil {
+			return fmt.Errorf("duplicate genesis upload %s", upload.TranscodedCid)
+		} else if !errors.Is(err, pebble.ErrNotFound) {
+			return err
+		}
+		_, err = store.GetUploadByOriginalCID(upload.OriginalCid)
+		if err == nil {
+			return fmt.Errorf("duplicate genesis original %s", upload.OriginalCid)
+		} else if !errors.Is(err, pebble.ErrNotFound) {
+			return err
+		}
+		if err := store.StoreUpload(upload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-		if fileUpload := signedTx.Transaction.Body.GetFileUpload(); fileUpload != nil {
-			msg := fileUpload.Msg
-			if msg == nil {
-				continue
-			}
+// ExportGenesis exports every upload in transcoded CID order.
+func ExportGenesis(ctx context.Context, store *chainstore.ChainStore, state *chainv1.GenesisState) error {
+	return store.IterateUploads(func(upload *storagev1.FileUploadMessage) error {
+		state.Uploads = append(state.Uploads, upload)
+		return nil
+	})
+}
 
-			// Store in chainstore
-			if err := s.chainStore.StoreUpload(msg); err != nil {
-				s.Logger.Errorf("failed to store upload in chainstore: %v", err)
-				continue
-			}
+// Queries
 
-			// Delete original upload from localstore
-			if err := s.localStore.DeleteUpload(msg.OriginalCid); err != nil {
-				s.Logger.Warnf("failed to delete original upload: %v", err)
-			}
+// RegisterQueryHandlers registers the state queries served by the storage module.
+func (s *StorageService) RegisterQueryHandlers(router module.QueryRouter) {
+	router.RegisterQueryHandler("/upload", s.QueryUpload)
+	router.RegisterQueryHandler("/upload/original", s.QueryUploadByOriginalCID)
+}
 
-			s.Logger.Infof("finalized file upload: original=%s transcoded=%s", msg.OriginalCid, msg.TranscodedCid)
-		}
+// QueryUpload serves /upload/{cid} by transcoded CID.
+func (s *StorageService) QueryUpload(ctx context.Context, store *chainstore.ChainStore, args []string) ([]byte, error) {
+	if len(args) != 1 || args[0] == "" {
+		return nil, fmt.Errorf("usage: /upload/{cid}")
 	}
+	return chainstore.UploadKey(args[0]), nil
+}
 
-	return &abcitypes.FinalizeBlockResponse{}, nil
+// QueryUploadByOriginalCID serves /upload/original/{cid}, the original CID
+// index entry holding the transcoded CID of the upload. The upload itself is
+// then read from /upload/{cid}, so each result is proven on its own.
+func (s *StorageService) QueryUploadByOriginalCID(ctx context.Context, store *chainstore.ChainStore, args []string) ([]byte, error) {
+	if len(args) != 1 || args[0] == "" {
+		return nil, fmt.Errorf("usage: /upload/original/{cid}")
+	}
+	return chainstore.UploadOriginalIndexKey(args[0]), nil
+}
+
+func (s *StorageService) HandleFileUpload(ctx context.Context, tx *module.Tx) error {
+	msg := tx.Body().GetFileUpload().GetMsg()
+	if msg == nil {
+		return fmt.Errorf("file upload transaction missing message")
+	}
+	if msg.OriginalCid == "" || msg.TranscodedCid == "" {
+		return fmt.Errorf("file upload missing cid")
+	}
+	// only the transcoder vouches for an upload, and nodes delete the
+	// original once it is recorded
+	if msg.TranscoderAddress != tx.Header().GetSender() {
+		return fmt.Errorf("file upload transcoded by %s sent by %s", msg.TranscoderAddress, tx.Header().GetSender())
+	}
+	if _, err := tx.Store.GetUpload(msg.TranscodedCid); err == nil {
+		return fmt.Errorf("upload %s already recorded", msg.TranscodedCid)
+	} else if !errors.Is(err, pebble.ErrNotFound) {
+		return err
+	}
+	if _, err := tx.Store.GetUploadByOriginalCID(msg.OriginalCid); err == nil {
+		return fmt.Errorf("original %s already recorded", msg.OriginalCid)
+	} else if !errors.Is(err, pebble.ErrNotFound) {
+		return err
+	}
+
+	// Store in the transaction's branch so a failed tx is rolled back
+	if err := tx.Store.StoreUpload(msg); err != nil {
+		return fmt.Errorf("failed to store upload in chainstore: %w", err)
+	}
+
+	s.Logger.Infof("finalized file upload: original=%s transcoded=%s", msg.OriginalCid, msg.TranscodedCid)
+
+	return tx.EmitEvent(&chainv1.TransactionEvent{
+		Event: &chainv1.TransactionEvent_FileUpload{
+			FileUpload: &chainv1.FileUploadEvent{
+				UploaderAddress:   msg.UploaderAddress,
+				TranscoderAddress: msg.TranscoderAddress,
+				OriginalCid:       msg.OriginalCid,
+				TranscodedCid:     msg.TranscodedCid,
+				TxHash:            tx.HashHex(),
+				BlockHeight:       uint64(tx.Height),
+			},
+		},
+	})
 }